@@ -1,25 +1,25 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	run "runtime"
 	"strings"
 	"time"
 
+	audit "Golang-WSL-GUI/src/Audit"
+	catalog "Golang-WSL-GUI/src/Catalog"
+	network "Golang-WSL-GUI/src/Network"
 	setting "Golang-WSL-GUI/src/Setting"
 	start "Golang-WSL-GUI/src/Start"
 	"Golang-WSL-GUI/src/installWSL"
 	runtimeGUI "Golang-WSL-GUI/src/runtimeGUI"
+	"Golang-WSL-GUI/src/wslcli"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
-	"golang.org/x/text/encoding/unicode"
-	"golang.org/x/text/transform"
 )
 
 type MigrationOptions struct {
@@ -28,6 +28,12 @@ type MigrationOptions struct {
 	DistroName string `json:"distroName"`
 }
 
+// Install_Bottom 的结果,预演时只有Plan,不提交任务
+type InstallResult struct {
+	JobID string           `json:"jobId"`
+	Plan  *installWSL.Plan `json:"plan"`
+}
+
 type App struct {
 	ctx context.Context
 }
@@ -36,12 +42,68 @@ var WSL_Regedit_Info = map[string]runtimeGUI.Regedit_WSL{}
 
 var WSLinfoMap = map[string]installWSL.WSLinfo{}
 
+// 卸载、迁移前等待发行版停止的时限
+const stopTimeout = 60 * time.Second
+
+// 绑定方法返回的错误,wsl.exe 错误以结构化对象传给前端,其余为字符串
+func formatError(err error) any {
+	if e := wslcli.Classify(err); e != nil {
+		return e
+	}
+	return err.Error()
+}
+
 func NewApp() *App {
 	return &App{}
 }
 
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	// 应用代理与证书设置,失败时退回默认网络配置
+	if err := network.Apply(setting.LoadAppSettings().Network); err != nil {
+		fmt.Printf("网络设置无效: %v\n", err)
+	}
+	// 安装队列变化时推送给前端
+	if n := setting.LoadAppSettings().InstallParallelism; n > 0 {
+		installWSL.DefaultQueue.SetLimit(n)
+	}
+	installWSL.DefaultQueue.OnChange = func(jobs []installWSL.JobInfo) {
+		runtime.EventsEmit(a.ctx, "install:queue", jobs)
+	}
+	// 订阅的发行版每次采样后推送给前端
+	if n := setting.LoadAppSettings().MetricsInterval; n > 0 {
+		runtimeGUI.DefaultMetrics.SetInterval(ctx, time.Duration(n)*time.Second)
+	}
+	runtimeGUI.DefaultMetrics.OnUpdate = func(m runtimeGUI.MetricsUpdate) {
+		runtime.EventsEmit(a.ctx, "metrics:update", m)
+	}
+	// 后台加载远程与用户自定义清单
+	go a.reloadCatalog()
+}
+
+// 程序退出时结束所有采样进程
+func (a *App) shutdown(ctx context.Context) {
+	runtimeGUI.DefaultMetrics.Stop()
+}
+
+// 清单来源
+func catalogSources() catalog.Sources {
+	return catalog.Sources{
+		OverridePath: setting.CatalogOverridePath(),
+		RemoteURL:    setting.LoadAppSettings().CatalogURL,
+		Client:       network.Client(),
+	}
+}
+
+// 重新加载清单并通知前端,返回加载失败的来源
+func (a *App) reloadCatalog() []string {
+	warnings := catalog.Reload(a.ctx, catalogSources())
+	messages := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		messages = append(messages, w.Error())
+	}
+	runtime.EventsEmit(a.ctx, "catalog:updated", messages)
+	return messages
 }
 
 // SelectDirectory 弹出系统原生目录选择框
@@ -59,41 +121,252 @@ func (a *App) SelectDirectory() string {
 	return path
 }
 
-func (a *App) Install_Bottom(name string, user string, pass string, ver string, path string, threadCount int) string {
+// SelectImageFile 弹出本地镜像文件选择框
+func (a *App) SelectImageFile() string {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择发行版镜像",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "WSL 镜像 (*.wsl;*.tar;*.tar.gz;*.tgz;*.tar.xz)", Pattern: "*.wsl;*.tar;*.tar.gz;*.tgz;*.tar.xz"},
+		},
+	})
+	if err != nil {
+		fmt.Printf("选择文件时出错: %v\n", err)
+		return ""
+	}
+	return path
+}
+
+// 默认安装目录
+func defaultInstallPath() string {
+	return fmt.Sprintf(`C:\Users\%s\AppData\Local\Packages`, os.Getenv("USERNAME"))
+}
+
+// 将安装加入队列,立即返回任务ID,进度通过事件推送;dryRun为true时只返回将执行的操作
+func (a *App) Install_Bottom(name string, user string, pass string, ver string, path string, threadCount int, dryRun bool) (InstallResult, error) {
+	if err := installWSL.ValidateCredentials(user, pass); err != nil {
+		return InstallResult{}, err
+	}
 	if path == "" {
-		path = fmt.Sprintf(`C:\Users\%s\AppData\Local\Packages`, os.Getenv("USERNAME"))
+		path = defaultInstallPath()
 	}
 
 	Info := installWSL.WSLinfo{
-		Linux_Version:   ver,
-		Install_Path:    &installWSL.WSLpath{Path: path},
-		Auth:            &installWSL.WSLAuth{User: user, Password: pass},
-		DownloadThreads: &installWSL.WSLDownload{DownloadThreads: threadCount},
-	}
-	if err := installWSL.WSL2_Downloader(a.ctx, Info); err != nil {
-		if err.Error() == "发行版存在,但未配置默认用户" {
-			if installWSL.WSL2_Setting_User(a.ctx, Info) != nil {
-				return err.Error()
+		Linux_Version: ver,
+		Install_Path:  &installWSL.WSLpath{Path: path},
+		Auth:          &installWSL.WSLAuth{User: user, Password: pass},
+		DownloadThreads: &installWSL.WSLDownload{
+			DownloadThreads: threadCount,
+			PreferredMirror: setting.LoadAppSettings().PreferredMirror,
+		},
+	}
+	if dryRun {
+		plan, err := installWSL.PlanInstall(Info)
+		return InstallResult{Plan: plan}, err
+	}
+	jobID, err := a.startInstallJob("Install_Bottom", Info, func(ctx context.Context) error {
+		if err := installWSL.WSL2_Downloader(ctx, Info); err != nil {
+			if err.Error() == "发行版存在,但未配置默认用户" {
+				if err := installWSL.WSL2_Setting_User(ctx, Info); err != nil {
+					return err
+				}
+				installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseDone, MessageKey: installWSL.MsgInstallDone})
+				return nil
 			}
-			runtime.EventsEmit(a.ctx, "wsl-output", "success")
-			return "success"
+			return err
 		}
-		return ""
+		if err := installWSL.WSL2_Installer(ctx, Info); err != nil {
+			return err
+		}
+		if err := installWSL.WSL2_Setting_User(ctx, Info); err != nil {
+			return err
+		}
+		installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseDone, MessageKey: installWSL.MsgInstallDone})
+		return nil
+	})
+	return InstallResult{JobID: jobID}, err
+}
+
+// 提交安装任务到队列,被取消时清理残留并通知前端
+func (a *App) startInstallJob(initiator string, Info installWSL.WSLinfo, run func(ctx context.Context) error) (string, error) {
+	job, err := installWSL.DefaultQueue.Submit(a.bindingCtx(initiator), "install", Info.Linux_Version, run, func(job *installWSL.Job, ctx context.Context, err error) {
+		switch {
+		case err == nil:
+		case ctx.Err() != nil:
+			// 排队中被取消的任务没有留下任何文件
+			if job.Started() {
+				installWSL.CleanupInstall(ctx, Info, job)
+			}
+			installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseCancelled, MessageKey: installWSL.MsgInstallCancel})
+			runtime.EventsEmit(a.ctx, "install:cancelled", map[string]interface{}{
+				"jobId":  job.ID,
+				"distro": Info.Linux_Version,
+			})
+		default:
+			installWSL.EmitFailed(ctx, wslcli.Describe(err))
+		}
+	})
+	if err != nil {
+		return "", err
 	}
-	time.Sleep(2 * time.Second)
-	if err := installWSL.WSL2_Installer(a.ctx, Info); err != nil {
-		return err.Error()
+	return job.ID, nil
+}
+
+// 获取安装任务最后一次进度,用于界面重新打开时恢复
+func (a *App) GetInstallProgress(jobID string) (installWSL.Progress, error) {
+	p, ok := installWSL.LastProgress(jobID)
+	if !ok {
+		return installWSL.Progress{}, installWSL.ErrJobNotFound
+	}
+	return p, nil
+}
+
+// 取消排队中或正在执行的安装任务
+func (a *App) CancelInstall(jobID string) error {
+	return installWSL.DefaultQueue.Cancel(jobID)
+}
+
+// 获取安装队列
+func (a *App) ListInstallJobs() []installWSL.JobInfo {
+	return installWSL.DefaultQueue.List()
+}
+
+// 调整排队中任务的顺序,index从0开始
+func (a *App) ReorderInstallJob(jobID string, index int) error {
+	return installWSL.DefaultQueue.Reorder(jobID, index)
+}
+
+// 清除已结束的任务记录
+func (a *App) ClearFinishedInstallJobs() {
+	installWSL.DefaultQueue.ClearFinished()
+}
+
+// 获取同时安装的任务数上限
+func (a *App) GetInstallParallelism() int {
+	return installWSL.DefaultQueue.Limit()
+}
+
+// 设置同时安装的任务数上限并保存,返回实际生效的值
+func (a *App) SetInstallParallelism(n int) (int, error) {
+	limit := installWSL.DefaultQueue.SetLimit(n)
+	s := setting.LoadAppSettings()
+	s.InstallParallelism = limit
+	return limit, setting.SaveAppSettings(s)
+}
+
+// 获取发行版清单
+func (a *App) GetCatalog() catalog.Catalog {
+	return *catalog.Current()
+}
+
+// 重新加载发行版清单
+func (a *App) ReloadCatalog() []string {
+	return a.reloadCatalog()
+}
+
+// 设置远程清单地址,为空时只使用内置清单和用户覆盖文件
+func (a *App) SetCatalogURL(url string) ([]string, error) {
+	s := setting.LoadAppSettings()
+	s.CatalogURL = strings.TrimSpace(url)
+	if err := setting.SaveAppSettings(s); err != nil {
+		return nil, err
 	}
-	if err := installWSL.WSL2_Setting_User(a.ctx, Info); err != nil {
-		return err.Error()
+	return a.reloadCatalog(), nil
+}
+
+// 设置首选下载镜像,如 tuna、ustc,为空时自动选择
+func (a *App) SetPreferredMirror(host string) error {
+	s := setting.LoadAppSettings()
+	s.PreferredMirror = strings.TrimSpace(host)
+	return setting.SaveAppSettings(s)
+}
+
+// 获取网络设置,代理密码只返回是否已设置
+func (a *App) GetNetworkSettings() network.Settings {
+	return setting.LoadAppSettings().Network.Masked()
+}
+
+// 校验并保存网络设置,立即对之后的请求生效
+func (a *App) SaveNetworkSettings(ns network.Settings) error {
+	s := setting.LoadAppSettings()
+	ns = ns.KeepPassword(s.Network)
+	if err := ns.Validate(); err != nil {
+		return err
+	}
+	s.Network = ns
+	if err := setting.SaveAppSettings(s); err != nil {
+		return err
+	}
+	return network.Apply(ns)
+}
+
+// 获取本地镜像缓存概况
+func (a *App) GetImageCache() (installWSL.CacheSummary, error) {
+	return installWSL.DefaultCache.Summary()
+}
+
+// 清空本地镜像缓存
+func (a *App) PurgeImageCache() error {
+	return installWSL.DefaultCache.Purge()
+}
+
+// 从本地镜像文件安装发行版,checksum为空时跳过校验,返回任务ID
+func (a *App) InstallFromFile(name string, file string, user string, pass string, path string, checksum string) (string, error) {
+	if !installWSL.ValidDistroName(name) {
+		return "", fmt.Errorf("发行版名称 %s 不符合规范", name)
+	}
+	if err := installWSL.ValidateCredentials(user, pass); err != nil {
+		return "", err
 	}
-	runtime.EventsEmit(a.ctx, "wsl-output", "success")
-	return "success"
+	if path == "" {
+		path = defaultInstallPath()
+	}
+
+	Info := installWSL.WSLinfo{
+		Linux_Version: name,
+		Install_Path:  &installWSL.WSLpath{Path: path},
+		Auth:          &installWSL.WSLAuth{User: user, Password: pass},
+		Local_File:    file,
+	}
+	return a.startInstallJob("InstallFromFile", Info, func(ctx context.Context) error {
+		if err := installWSL.WSL2_Check(ctx, Info); err != nil {
+			if err.Error() == "发行版存在,但未配置默认用户" {
+				if err := installWSL.WSL2_Setting_User(ctx, Info); err != nil {
+					return err
+				}
+				installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseDone, MessageKey: installWSL.MsgInstallDone})
+				return nil
+			}
+			return err
+		}
+
+		installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseVerifying, MessageKey: installWSL.MsgVerifyLocal})
+		importFile, cleanup, err := installWSL.WSL2_LocalImage(ctx, Info, checksum)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			installWSL.EmitFailed(ctx, fmt.Sprintf("本地镜像校验失败: %v", err))
+			return err
+		}
+		defer cleanup()
+		Info := Info
+		Info.Local_File = importFile
+		installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseVerifying, MessageKey: installWSL.MsgVerifyLocalDone})
+
+		if err := installWSL.WSL2_Installer(ctx, Info); err != nil {
+			return err
+		}
+		if err := installWSL.WSL2_Setting_User(ctx, Info); err != nil {
+			return err
+		}
+		installWSL.EmitProgress(ctx, installWSL.Progress{Phase: installWSL.PhaseDone, MessageKey: installWSL.MsgInstallDone})
+		return nil
+	})
 }
 
 // 获取WSL基本信息
 func (a *App) GetDistroStats() ([]*runtimeGUI.List, error) {
-	Info, err := runtimeGUI.GetWSLallStatus()
+	Info, err := runtimeGUI.GetWSLallStatus(a.bindingCtx("GetDistroStats"))
 	if err != nil {
 		return nil, err
 	}
@@ -111,64 +384,115 @@ func (a *App) GetPath(name string) (string, error) {
 }
 
 // 获取WSL发行版运行信息
-func (a *App) GetMetrics(name string) runtimeGUI.Metrics {
-	Info := installWSL.WSLinfo{
-		Linux_Version:   name,
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
-	}
-	ptr, err := runtimeGUI.GetMetrics_Runtime(Info)
+func (a *App) GetMetrics(name string) (runtimeGUI.Metrics, error) {
+	ptr, err := runtimeGUI.GetMetrics_Runtime(a.bindingCtx("GetMetrics"), name)
 	if err != nil {
-		return runtimeGUI.Metrics{}
+		return runtimeGUI.Metrics{}, err
 	}
-	return *ptr
+	return *ptr, nil
 }
 
-// UninstallDistro 卸载发行版
-func (a *App) UninstallDistro(name string) error {
-	Info := installWSL.WSLinfo{
-		Linux_Version:   name,
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
+// 订阅发行版指标,运行中时按间隔推送 metrics:update 事件
+func (a *App) SubscribeMetrics(name string) error {
+	return runtimeGUI.DefaultMetrics.Subscribe(a.bindingCtx("SubscribeMetrics"), name)
+}
+
+// 取消订阅,卡片不可见时调用
+func (a *App) UnsubscribeMetrics(name string) {
+	runtimeGUI.DefaultMetrics.Unsubscribe(name)
+}
+
+// 发行版的历史指标,range 为 5m、1h 或 24h
+func (a *App) GetMetricsHistory(name string, rng runtimeGUI.MetricsRange) ([]runtimeGUI.MetricsPoint, error) {
+	return runtimeGUI.DefaultMetrics.History(name, rng)
+}
+
+// 导出历史指标为CSV或JSON,返回保存路径,取消时为空
+func (a *App) ExportMetricsHistory(name string, rng runtimeGUI.MetricsRange) (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出指标历史",
+		DefaultFilename: fmt.Sprintf("%s-metrics-%s-%s.csv", name, rng, time.Now().Format("20060102-150405")),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV (*.csv)", Pattern: "*.csv"},
+			{DisplayName: "JSON (*.json)", Pattern: "*.json"},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
 	}
-	installWSL.Start_cmd(Info, "Shutdown")
-	runtime.EventsEmit(a.ctx, "uninstall:progress", fmt.Sprintf("正在停止 %s 发行版", Info.Linux_Version))
-	// 确保完全关闭
-	for {
-		listptr, _ := runtimeGUI.GetWSLallStatus()
-
-		isRunning := false
-		for _, distro := range listptr {
-			if distro.Name == Info.Linux_Version {
-				if distro.Status == "Running" {
-					isRunning = true
-				}
-				break
-			}
-		}
+	if err := runtimeGUI.DefaultMetrics.ExportHistory(path, name, rng); err != nil {
+		return "", err
+	}
+	return path, nil
+}
 
-		if !isRunning {
-			break
-		}
+// 获取指标采样间隔秒数
+func (a *App) GetMetricsInterval() int {
+	return int(runtimeGUI.DefaultMetrics.Interval() / time.Second)
+}
+
+// 设置指标采样间隔并保存,返回实际生效的秒数
+func (a *App) SetMetricsInterval(seconds int) (int, error) {
+	d := runtimeGUI.DefaultMetrics.SetInterval(a.bindingCtx("SetMetricsInterval"), time.Duration(seconds)*time.Second)
+	s := setting.LoadAppSettings()
+	s.MetricsInterval = int(d / time.Second)
+	return s.MetricsInterval, setting.SaveAppSettings(s)
+}
+
+// 发行版内的进程列表,CPU占用为约1秒内的采样
+func (a *App) GetProcesses(name string, query runtimeGUI.ProcessQuery) ([]runtimeGUI.Process, error) {
+	return runtimeGUI.GetProcesses(a.bindingCtx("GetProcesses"), name, query)
+}
 
-		time.Sleep(2 * time.Second)
+// 向发行版内的进程发送信号
+func (a *App) KillProcess(name string, pid int, signal runtimeGUI.ProcessSignal) error {
+	return runtimeGUI.KillProcess(a.bindingCtx("KillProcess"), name, pid, signal)
+}
+
+// 发行版的网卡与监听端口,并标出按 .wslconfig 无法从Windows访问的端口
+func (a *App) InspectNetwork(name string) (*runtimeGUI.NetworkReport, error) {
+	return runtimeGUI.InspectNetwork(a.bindingCtx("InspectNetwork"), name)
+}
+
+// UninstallDistro 卸载发行版,dryRun为true时只返回将执行的操作
+func (a *App) UninstallDistro(name string, dryRun bool) (*installWSL.Plan, error) {
+	Info := installWSL.WSLinfo{Linux_Version: name}
+	if dryRun {
+		return planUninstall(Info), nil
+	}
+	ctx := a.bindingCtx("UninstallDistro")
+	runtime.EventsEmit(a.ctx, "uninstall:progress", fmt.Sprintf("正在停止 %s 发行版", Info.Linux_Version))
+	// 确保完全关闭
+	if err := stopDistro(ctx, wslcli.Default, name); err != nil {
+		runtime.EventsEmit(a.ctx, "uninstall:failed", fmt.Sprintf("停止 %s 发行版失败: %s", Info.Linux_Version, err))
+		return nil, err
 	}
 	runtime.EventsEmit(a.ctx, "uninstall:progress", fmt.Sprintf("开始卸载 %s 发行版", Info.Linux_Version))
-	if err := installWSL.UninstallWSL(a.ctx, Info); err != nil {
-		return err
+	if err := installWSL.UninstallWSL(ctx, Info); err != nil {
+		return nil, err
 	}
 	runtime.EventsEmit(a.ctx, "uninstall:progress", "success")
 	// 执行 wsl --unregister <name>
-	return nil
+	return nil, nil
+}
+
+// 结束发行版并等待其停止,超时返回 wslcli.TimeoutError;预演时cli为记录用的客户端
+func stopDistro(ctx context.Context, cli wslcli.Client, name string) error {
+	if err := cli.Terminate(ctx, name); err != nil {
+		return err
+	}
+	return wslcli.WaitStopped(ctx, cli, name, stopTimeout)
+}
+
+// 以绑定方法名标记发起者,该ctx执行的命令都会记入审计日志
+func (a *App) bindingCtx(name string) context.Context {
+	return audit.WithInitiator(a.ctx, name)
 }
 
 // 检查管理员权限
 func (a *App) CheckAdmin() bool {
 	if run.GOOS == "windows" {
-		cmd := exec.Command("net", "session")
-		err := cmd.Run()
+		_, err := audit.Run(a.bindingCtx("CheckAdmin"), exec.Command("net", "session"))
 		return err == nil
 	}
 	// 非 Windows 系统根据逻辑返回
@@ -178,14 +502,17 @@ func (a *App) CheckAdmin() bool {
 // 检查WSL功能组件
 func (a *App) CheckWSL() bool {
 	if run.GOOS == "windows" {
-		err := start.DetectWSL()
+		err := start.DetectWSL(a.bindingCtx("CheckWSL"))
 		return err == nil
 	}
 	return false
 }
 
-// 迁移WSL系统函数
-func (a *App) StartMigration(option MigrationOptions) error {
+// 迁移WSL系统函数,dryRun为true时只返回将执行的操作
+func (a *App) StartMigration(option MigrationOptions, dryRun bool) (*installWSL.Plan, error) {
+	if dryRun {
+		return planMigration(option), nil
+	}
 	Info := installWSL.WSLinfo{
 		Linux_Version:   option.DistroName,
 		Install_Path:    &installWSL.WSLpath{Path: option.TargetPath},
@@ -193,9 +520,10 @@ func (a *App) StartMigration(option MigrationOptions) error {
 		DownloadThreads: nil,
 	}
 
-	user, err := runtimeGUI.GetDefaultUser(Info)
+	ctx := a.bindingCtx("StartMigration")
+	user, err := runtimeGUI.GetDefaultUser(ctx, option.DistroName)
 	if err != nil {
-		return errors.New(user)
+		return nil, errors.New(user)
 	}
 	// 刷新Info
 	Info = installWSL.WSLinfo{
@@ -205,77 +533,46 @@ func (a *App) StartMigration(option MigrationOptions) error {
 		DownloadThreads: nil,
 	}
 
-	installWSL.Start_cmd(Info, "Stop")
 	// 确保完全关闭
-	for {
-		listptr, _ := runtimeGUI.GetWSLallStatus()
-
-		isRunning := false
-		for _, distro := range listptr {
-			if distro.Name == option.DistroName {
-				if distro.Status == "Running" {
-					isRunning = true
-				}
-				break
-			}
-		}
-
-		if !isRunning {
-			break
-		}
-
-		time.Sleep(2 * time.Second)
+	if err := stopDistro(ctx, wslcli.Default, option.DistroName); err != nil {
+		return nil, err
 	}
 	runtime.EventsEmit(a.ctx, "migration:progress", "迁移准备工作完成")
 	time.Sleep(2 * time.Second)
 	// 异步处理,防止堵塞
-	go installWSL.MovingPathWSL(a.ctx, Info)
+	go installWSL.MovingPathWSL(ctx, Info)
 	// 已接收
-	return nil
+	return nil, nil
 }
 
 // 打开发行版内部目录
 func (a *App) OpenDistroFolder(distroName string) error {
-	Info := installWSL.WSLinfo{
-		Linux_Version:   distroName,
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
-	}
-	defaultUser, err := runtimeGUI.GetDefaultUser(Info)
+	ctx := a.bindingCtx("OpenDistroFolder")
+	defaultUser, err := runtimeGUI.GetDefaultUser(ctx, distroName)
 	if err != nil {
 		cmd := exec.Command("explorer.exe", fmt.Sprintf(`\\wsl$\%s\home`, distroName))
-		return cmd.Start()
+		return audit.Start(ctx, cmd)
 	}
 	// Windows下调用explorer
 	cmd := exec.Command("explorer.exe", fmt.Sprintf(`\\wsl$\%s\home\%s`, distroName, defaultUser))
-	return cmd.Start()
+	return audit.Start(ctx, cmd)
 }
 
 // 启动发行版按钮
 func (a *App) StartDistro(name string) {
-	Info := installWSL.WSLinfo{
-		Linux_Version:   name,
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
-	}
-	installWSL.Start_cmd(Info, "Start")
+	wslcli.Default.Launch(a.bindingCtx("StartDistro"), name)
 }
 
-// .wslconfig全局性能写入配置
-func (a *App) SavePerformanceConfig(config setting.PerformanceConfig) error {
-	if err := setting.Wriding_PerformanceConfig(config); err != nil {
-		return err
+// .wslconfig全局性能写入配置,dryRun为true时只返回将执行的操作
+func (a *App) SavePerformanceConfig(config setting.PerformanceConfig, dryRun bool) (*installWSL.Plan, error) {
+	if dryRun {
+		return planPerformanceConfig(config)
 	}
-	Info := installWSL.WSLinfo{
-		Linux_Version:   "",
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
+	if err := setting.Wriding_PerformanceConfig(config); err != nil {
+		return nil, err
 	}
-	installWSL.Start_cmd(Info, "ShutdownAll")
-	return nil
+	wslcli.Default.Shutdown(a.bindingCtx("SavePerformanceConfig"))
+	return nil, nil
 }
 
 // .wslconfig全局性能读取配置
@@ -285,47 +582,17 @@ func (a *App) GetPerformanceConfig() setting.PerformanceConfig {
 
 // 获取 WSL 版本
 func (a *App) GetWSLVersion() string {
-	Info := installWSL.WSLinfo{
-		Linux_Version:   "",
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
-	}
-	return setting.GetOnlyWslVersion(Info)
+	return setting.GetOnlyWslVersion(a.bindingCtx("GetWSLVersion"))
 }
 
 // 显示详细版本信息
 func (a *App) ShowWSLInfo() string {
-	Info := installWSL.WSLinfo{
-		Linux_Version:   "",
-		Install_Path:    nil,
-		Auth:            nil,
-		DownloadThreads: nil,
-	}
-
-	line, err := installWSL.Start_cmd(Info, "Version")
+	info, err := wslcli.Default.Version(a.bindingCtx("ShowWSLInfo"))
 	if err != nil {
-		return err.Error()
-	}
-
-	// 定义 UTF-16LE 解码器
-	decoder := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()
-	// 尝试解码
-	decoded, err := io.ReadAll(transform.NewReader(bytes.NewReader(line), decoder))
-
-	var result string
-	if err != nil || len(decoded) < 2 {
-		result = string(line)
-	} else {
-		result = string(decoded)
+		return wslcli.Describe(err)
 	}
-
-	// 去除不可见的 BOM 头、回车符 \r 和多余空格
-	result = strings.ReplaceAll(result, "\uFEFF", "") // 去除 UTF-16 BOM
-	result = strings.ReplaceAll(result, "\r", "")     // 统一换行符
-	result = strings.TrimSpace(result)                // 去除首尾空白
-
-	return result
+	// 去除首尾空白
+	return strings.TrimSpace(wslcli.DecodeOutput(info.Output))
 }
 
 // CheckAndUpdateWSL 检查并更新 WSL
@@ -336,5 +603,36 @@ func (a *App) CheckAndUpdateWSL() {
 
 	// 推荐方式 (简单有效): 打开外部终端执行更新
 	// Windows 命令: start cmd /k "wsl --update"
-	exec.Command("cmd", "/c", "start", "cmd", "/k", "wsl --update").Start()
+	audit.Start(a.bindingCtx("CheckAndUpdateWSL"), exec.Command("cmd", "/c", "start", "cmd", "/k", "wsl --update"))
+}
+
+// 查询本程序执行过的命令记录,从新到旧
+func (a *App) GetCommandHistory(filter audit.Filter) ([]audit.Entry, error) {
+	entries, err := audit.Default.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+	return entries, nil
+}
+
+// 导出命令记录到用户选择的文件,取消选择时返回空路径
+func (a *App) ExportCommandHistory(filter audit.Filter) (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出命令记录",
+		DefaultFilename: fmt.Sprintf("wsl-commands-%s.csv", time.Now().Format("20060102-150405")),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV (*.csv)", Pattern: "*.csv"},
+			{DisplayName: "JSON Lines (*.jsonl)", Pattern: "*.jsonl"},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if err := audit.Default.Export(path, filter); err != nil {
+		return "", err
+	}
+	return path, nil
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	setting "Golang-WSL-GUI/src/Setting"
+	"Golang-WSL-GUI/src/installWSL"
+	runtimeGUI "Golang-WSL-GUI/src/runtimeGUI"
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 发行版注册信息所在的注册表键
+const lxssKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\Lxss`
+
+// UninstallDistro 的预演
+func planUninstall(Info installWSL.WSLinfo) *installWSL.Plan {
+	p := installWSL.NewPlan("uninstall", Info.Linux_Version)
+	disk := planDisk(p, Info.Linux_Version)
+	planStopDistro(p, Info.Linux_Version)
+	installWSL.PlanUninstall(p, Info, disk)
+	return p
+}
+
+// StartMigration 的预演
+func planMigration(option MigrationOptions) *installWSL.Plan {
+	p := installWSL.NewPlan("migration", option.DistroName)
+	disk := planDisk(p, option.DistroName)
+	p.Wsl("读取默认用户", func(ctx context.Context, cli wslcli.Client) {
+		wslcli.ReadWslConf(ctx, cli, option.DistroName)
+	})
+	planStopDistro(p, option.DistroName)
+	p.Add(installWSL.PlanStep{Kind: installWSL.StepWait, Description: "等待 2s 后开始迁移"})
+
+	Info := installWSL.WSLinfo{
+		Linux_Version: option.DistroName,
+		Install_Path:  &installWSL.WSLpath{Path: option.TargetPath},
+		// 预演时不读取发行版,用户名以占位符表示
+		Auth: &installWSL.WSLAuth{User: "<默认用户>"},
+	}
+	installWSL.PlanMigration(p, Info, disk)
+	return p
+}
+
+// SavePerformanceConfig 的预演
+func planPerformanceConfig(config setting.PerformanceConfig) (*installWSL.Plan, error) {
+	p := installWSL.NewPlan("performance-config", "")
+	path, size, err := setting.PlanPerformanceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	p.Add(installWSL.PlanStep{Kind: installWSL.StepFileWrite, Description: "覆盖写入 .wslconfig", Path: path, Size: size})
+	p.Wsl("关闭所有发行版使配置生效", func(ctx context.Context, cli wslcli.Client) {
+		cli.Shutdown(ctx)
+	})
+	return p, nil
+}
+
+// 通过记录用的客户端执行 stopDistro
+func planStopDistro(p *installWSL.Plan, name string) {
+	p.Wsl(fmt.Sprintf("结束发行版,每秒查询一次等待其停止,最长 %s", stopTimeout), func(ctx context.Context, cli wslcli.Client) {
+		stopDistro(ctx, cli, name)
+	})
+}
+
+// 读取注册表确定虚拟磁盘位置与大小,读取失败时记录说明并返回nil
+func planDisk(p *installWSL.Plan, name string) *installWSL.PlanDisk {
+	p.Add(installWSL.PlanStep{
+		Kind:        installWSL.StepRegistryRead,
+		Description: fmt.Sprintf("查找 %s 的 BasePath 与 VhdFileName", name),
+		Path:        lxssKey,
+	})
+	info, err := runtimeGUI.Seach_WSL_Regedit_Info(name)
+	if err != nil {
+		p.Note("无法确定 %s 的虚拟磁盘: %v", name, err)
+		return nil
+	}
+	disk := &installWSL.PlanDisk{Path: filepath.Join(info.BasePath, info.VhdFileName)}
+	if stat, err := os.Stat(disk.Path); err == nil {
+		disk.Size = stat.Size()
+	}
+	return disk
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	catalog "Golang-WSL-GUI/src/Catalog"
+	setting "Golang-WSL-GUI/src/Setting"
+	"Golang-WSL-GUI/src/installWSL"
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 预演不应执行任何 wsl.exe 命令
+func fakeDefault(t *testing.T) *wslcli.FakeRunner {
+	t.Helper()
+	f := wslcli.NewFakeRunner()
+	prev := wslcli.Default
+	wslcli.Default = wslcli.New(f)
+	t.Cleanup(func() {
+		wslcli.Default = prev
+		if n := len(f.Calls()); n != 0 {
+			t.Errorf("预演执行了 %d 条命令", n)
+		}
+	})
+	return f
+}
+
+// 计划中的命令行
+func planCommands(p *installWSL.Plan) []string {
+	var cmds []string
+	for _, s := range p.Steps {
+		if s.Kind == installWSL.StepCommand {
+			cmds = append(cmds, strings.Join(s.Command, " "))
+		}
+	}
+	return cmds
+}
+
+func hasCommand(p *installWSL.Plan, prefix string) bool {
+	for _, c := range planCommands(p) {
+		if strings.HasPrefix(c, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInstallDryRun(t *testing.T) {
+	fakeDefault(t)
+	a := &App{}
+	ver := catalog.Current().Distros[0].Name
+	res, err := a.Install_Bottom("card", "bob", "hunter2", ver, t.TempDir(), 4, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.JobID != "" || res.Plan == nil {
+		t.Fatalf("预演结果 = %+v", res)
+	}
+	if res.Plan.Distro != ver || !hasCommand(res.Plan, "wsl.exe --import "+ver+" ") {
+		t.Fatalf("计划中没有导入 %s: %v", ver, planCommands(res.Plan))
+	}
+	if len(installWSL.DefaultQueue.List()) != 0 {
+		t.Fatal("预演提交了安装任务")
+	}
+	for _, c := range planCommands(res.Plan) {
+		if strings.Contains(c, "hunter2") {
+			t.Fatalf("计划中出现密码: %s", c)
+		}
+	}
+}
+
+func TestUninstallDryRun(t *testing.T) {
+	fakeDefault(t)
+	p, err := (&App{}).UninstallDistro("Ubuntu", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"wsl.exe --terminate Ubuntu", "wsl.exe --list --verbose", "wsl.exe --unregister Ubuntu"} {
+		if !hasCommand(p, want) {
+			t.Fatalf("计划中没有 %s: %v", want, planCommands(p))
+		}
+	}
+}
+
+func TestMigrationDryRun(t *testing.T) {
+	fakeDefault(t)
+	p, err := (&App{}).StartMigration(MigrationOptions{DistroName: "Ubuntu", TargetPath: t.TempDir()}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"wsl.exe --terminate Ubuntu", "wsl.exe --export Ubuntu", "wsl.exe --unregister Ubuntu", "wsl.exe --import Ubuntu"} {
+		if !hasCommand(p, want) {
+			t.Fatalf("计划中没有 %s: %v", want, planCommands(p))
+		}
+	}
+}
+
+func TestPerformanceConfigDryRun(t *testing.T) {
+	fakeDefault(t)
+	p, err := (&App{}).SavePerformanceConfig(setting.Rading_PerformanceConfig(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasCommand(p, "wsl.exe --shutdown") {
+		t.Fatalf("计划中没有关闭WSL: %v", planCommands(p))
+	}
+}
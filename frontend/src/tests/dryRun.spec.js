@@ -0,0 +1,28 @@
+import { describe, it, expect } from 'vitest'
+import { formatPlan } from '../utils/dryRun'
+
+describe('预演结果格式化', () => {
+  it('按顺序列出命令、路径与大小', () => {
+    const text = formatPlan({
+      operation: 'uninstall',
+      distro: 'Ubuntu',
+      steps: [
+        { kind: 'command', description: '结束发行版', command: ['wsl.exe', '--terminate', 'Ubuntu'], path: '', size: 0, condition: '' },
+        { kind: 'file-delete', description: 'wsl.exe 删除虚拟磁盘', command: null, path: 'D:\\WSL\\ext4.vhdx', size: 2 * 1024 * 1024 * 1024, condition: '' }
+      ],
+      notes: ['示例说明']
+    })
+    const lines = text.split('\n')
+    expect(lines[0]).toBe('1. [执行] 结束发行版')
+    expect(lines[1]).toBe('   wsl.exe --terminate Ubuntu')
+    expect(text).toContain('2. [删除] wsl.exe 删除虚拟磁盘')
+    expect(text).toContain('D:\\WSL\\ext4.vhdx (2.0 GB)')
+    expect(text).toContain('- 示例说明')
+  })
+
+  it('标注条件步骤并容忍空结果', () => {
+    expect(formatPlan(null)).toBe('')
+    const text = formatPlan({ steps: [{ kind: 'download', description: '下载镜像', condition: '缓存未命中' }] })
+    expect(text).toBe('1. [下载] 下载镜像 (仅当缓存未命中)')
+  })
+})
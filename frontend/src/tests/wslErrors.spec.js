@@ -0,0 +1,35 @@
+import { describe, it, expect } from 'vitest'
+import { isTimeoutError, isWslError, errorCategory, describeError } from '../utils/wslErrors'
+
+describe('WSL 错误识别', () => {
+  it('识别后端超时错误', () => {
+    const err = 'WSL_TIMEOUT: wsl.exe --list --verbose 在 15s 内未完成,WSL可能无响应'
+    expect(isTimeoutError(err)).toBe(true)
+    expect(isTimeoutError(new Error(err))).toBe(true)
+    expect(describeError(err)).toContain('wsl --shutdown')
+  })
+
+  it('结构化错误附带处理建议', () => {
+    const err = {
+      category: 'not-found',
+      code: 'Wsl/Service/WSL_E_DISTRO_NOT_FOUND',
+      message: '不存在具有所提供名称的分发。',
+      hint: '发行版或文件不存在,请刷新列表或检查路径',
+      exitCode: 1,
+      op: 'wsl.exe --unregister Ubuntu'
+    }
+    expect(isWslError(err)).toBe(true)
+    expect(errorCategory(err)).toBe('not-found')
+    expect(isTimeoutError(err)).toBe(false)
+    expect(describeError(err)).toBe('不存在具有所提供名称的分发。\n发行版或文件不存在,请刷新列表或检查路径')
+    expect(isTimeoutError({ ...err, category: 'timeout' })).toBe(true)
+  })
+
+  it('其他错误原样返回', () => {
+    expect(isTimeoutError('exit status 1')).toBe(false)
+    expect(isTimeoutError(undefined)).toBe(false)
+    expect(isWslError('exit status 1')).toBe(false)
+    expect(describeError('exit status 1')).toBe('exit status 1')
+    expect(describeError(new Error('boom'))).toBe('boom')
+  })
+})
@@ -0,0 +1,32 @@
+import { formatBytes } from './format'
+
+const KIND_LABELS = {
+  'command': '执行',
+  'file-write': '写入',
+  'file-delete': '删除',
+  'registry-read': '读取注册表',
+  'download': '下载',
+  'wait': '等待'
+}
+
+// 单个字节数,复用 formatBytes 的换算规则
+const formatSize = (bytes) => formatBytes(bytes, bytes).text.split(' / ')[0]
+
+// 将后端返回的预演结果格式化为逐行文本
+export const formatPlan = (plan) => {
+  if (!plan || !Array.isArray(plan.steps)) return ''
+
+  const lines = plan.steps.map((step, index) => {
+    let line = `${index + 1}. [${KIND_LABELS[step.kind] || step.kind}] ${step.description}`
+    if (step.condition) line += ` (仅当${step.condition})`
+    if (step.command && step.command.length) line += `\n   ${step.command.join(' ')}`
+    if (step.path) line += `\n   ${step.path}`
+    if (step.size > 0) line += ` (${formatSize(step.size)})`
+    return line
+  })
+
+  if (plan.notes && plan.notes.length) {
+    lines.push('', '说明:', ...plan.notes.map(note => `- ${note}`))
+  }
+  return lines.join('\n')
+}
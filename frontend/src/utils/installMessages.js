@@ -0,0 +1,57 @@
+// 安装进度文案，与后端 installWSL.Progress 的 messageKey 对应
+const messages = {
+  'check.start': '正在检查 {distro} 是否已安装',
+  'check.existing': '{distro} 发行版已安装，开始配置用户',
+  'download.cacheHit': '检测到本地缓存镜像，跳过下载',
+  'download.mirror': '正在从镜像 {host} 下载',
+  'download.resume': '检测到未完成的下载，从 {percent}% 继续',
+  'download.progress': '正在下载镜像: {percent}%',
+  'download.done': '下载完成，准备安装 {distro}',
+  'verify.local': '正在校验本地镜像',
+  'verify.localDone': '本地镜像校验完成，准备安装 {distro}',
+  'verify.sha256': '正在校验镜像 Sha256',
+  'import.start': '正在解压安装发行版 {distro}',
+  'import.done': '安装发行版 {distro} 成功',
+  'user.configure': '正在配置用户 {user}',
+  'user.detect': '正在识别发行版',
+  'user.detected': '识别为 {os}，管理员组 {group}，登录 shell {shell}',
+  'user.installSudo': '正在安装 sudo',
+  'user.create': '正在创建用户 {user}',
+  'user.password': '正在设置密码',
+  'user.group': '正在配置 {group} 组',
+  'user.sudoers': '正在配置 sudoers',
+  'user.default': '正在设置默认用户',
+  'install.done': '安装已完成！',
+  'install.failed': '安装失败: {error}',
+  'install.cancelled': '安装已取消',
+  'warning': '注意: {error}'
+}
+
+// 渲染文案，未登记的 key 原样返回
+export const renderMessage = (key, params = {}) => {
+  const template = messages[key]
+  if (!template) return key || ''
+  return template.replace(/\{(\w+)\}/g, (_, name) => params?.[name] ?? '')
+}
+
+// 下载速度，如 5.2 MB/s
+export const formatSpeed = (bytesPerSecond) => {
+  if (!bytesPerSecond || bytesPerSecond <= 0) return ''
+  const units = ['B/s', 'KB/s', 'MB/s', 'GB/s']
+  let value = bytesPerSecond
+  let i = 0
+  while (value >= 1024 && i < units.length - 1) {
+    value /= 1024
+    i++
+  }
+  return `${value.toFixed(i === 0 ? 0 : 1)} ${units[i]}`
+}
+
+// 剩余时间，如 1分20秒，未知时返回空串
+export const formatETA = (seconds) => {
+  if (seconds === undefined || seconds === null || seconds < 0 || !isFinite(seconds)) return ''
+  const s = Math.round(seconds)
+  if (s < 60) return `${s}秒`
+  if (s < 3600) return `${Math.floor(s / 60)}分${s % 60}秒`
+  return `${Math.floor(s / 3600)}小时${Math.floor((s % 3600) / 60)}分`
+}
@@ -0,0 +1,27 @@
+// 与后端 wslcli.TimeoutCode 对应
+const TIMEOUT_CODE = 'WSL_TIMEOUT'
+
+// 后端 wsl.exe 错误以 { category, code, message, hint, exitCode, op } 对象返回，其余错误为字符串
+export const isWslError = (err) => !!err && typeof err === 'object' && typeof err.category === 'string'
+
+// 错误分类，非 wsl.exe 错误返回空串
+export const errorCategory = (err) => (isWslError(err) ? err.category : '')
+
+// 是否为 wsl.exe 超时错误
+export const isTimeoutError = (err) => {
+  if (isWslError(err)) return err.category === 'timeout'
+  return String(err ?? '').includes(TIMEOUT_CODE)
+}
+
+// 错误提示文案，附带处理建议
+export const describeError = (err) => {
+  if (isWslError(err)) {
+    const message = err.message || err.code || '未知错误'
+    return err.hint ? `${message}\n${err.hint}` : message
+  }
+  if (isTimeoutError(err)) {
+    return 'WSL 长时间无响应，操作已中止。可尝试执行 wsl --shutdown 后重试'
+  }
+  if (err instanceof Error) return err.message
+  return String(err ?? '')
+}
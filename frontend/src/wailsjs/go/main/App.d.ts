@@ -1,37 +1,97 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {audit} from '../models';
 import {runtimeGUI} from '../models';
+import {catalog} from '../models';
+import {installWSL} from '../models';
+import {network} from '../models';
 import {setting} from '../models';
 import {main} from '../models';
 
+export function CancelInstall(arg1:string):Promise<void>;
+
 export function CheckAdmin():Promise<boolean>;
 
 export function CheckAndUpdateWSL():Promise<void>;
 
 export function CheckWSL():Promise<boolean>;
 
+export function ClearFinishedInstallJobs():Promise<void>;
+
+export function ExportCommandHistory(arg1:audit.Filter):Promise<string>;
+
+export function ExportMetricsHistory(arg1:string,arg2:runtimeGUI.MetricsRange):Promise<string>;
+
+export function GetCatalog():Promise<catalog.Catalog>;
+
+export function GetCommandHistory(arg1:audit.Filter):Promise<Array<audit.Entry>>;
+
 export function GetDistroStats():Promise<Array<runtimeGUI.List>>;
 
+export function GetImageCache():Promise<installWSL.CacheSummary>;
+
+export function GetInstallParallelism():Promise<number>;
+
+export function GetInstallProgress(arg1:string):Promise<installWSL.Progress>;
+
 export function GetMetrics(arg1:string):Promise<runtimeGUI.Metrics>;
 
+export function GetMetricsHistory(arg1:string,arg2:runtimeGUI.MetricsRange):Promise<Array<runtimeGUI.MetricsPoint>>;
+
+export function GetMetricsInterval():Promise<number>;
+
+export function GetNetworkSettings():Promise<network.Settings>;
+
 export function GetPath(arg1:string):Promise<string>;
 
 export function GetPerformanceConfig():Promise<setting.PerformanceConfig>;
 
+export function GetProcesses(arg1:string,arg2:runtimeGUI.ProcessQuery):Promise<Array<runtimeGUI.Process>>;
+
 export function GetWSLVersion():Promise<string>;
 
-export function Install_Bottom(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:number):Promise<string>;
+export function InspectNetwork(arg1:string):Promise<runtimeGUI.NetworkReport>;
+
+export function InstallFromFile(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<string>;
+
+export function Install_Bottom(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:number,arg7:boolean):Promise<main.InstallResult>;
+
+export function KillProcess(arg1:string,arg2:number,arg3:runtimeGUI.ProcessSignal):Promise<void>;
+
+export function ListInstallJobs():Promise<Array<installWSL.JobInfo>>;
 
 export function OpenDistroFolder(arg1:string):Promise<void>;
 
-export function SavePerformanceConfig(arg1:setting.PerformanceConfig):Promise<void>;
+export function PurgeImageCache():Promise<void>;
+
+export function ReloadCatalog():Promise<Array<string>>;
+
+export function ReorderInstallJob(arg1:string,arg2:number):Promise<void>;
+
+export function SaveNetworkSettings(arg1:network.Settings):Promise<void>;
+
+export function SavePerformanceConfig(arg1:setting.PerformanceConfig,arg2:boolean):Promise<installWSL.Plan>;
 
 export function SelectDirectory():Promise<string>;
 
+export function SelectImageFile():Promise<string>;
+
+export function SetCatalogURL(arg1:string):Promise<Array<string>>;
+
+export function SetInstallParallelism(arg1:number):Promise<number>;
+
+export function SetMetricsInterval(arg1:number):Promise<number>;
+
+export function SetPreferredMirror(arg1:string):Promise<void>;
+
 export function ShowWSLInfo():Promise<string>;
 
 export function StartDistro(arg1:string):Promise<void>;
 
-export function StartMigration(arg1:main.MigrationOptions):Promise<void>;
+export function StartMigration(arg1:main.MigrationOptions,arg2:boolean):Promise<installWSL.Plan>;
+
+export function SubscribeMetrics(arg1:string):Promise<void>;
+
+export function UninstallDistro(arg1:string,arg2:boolean):Promise<installWSL.Plan>;
 
-export function UninstallDistro(arg1:string):Promise<void>;
+export function UnsubscribeMetrics(arg1:string):Promise<void>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelInstall(arg1) {
+  return window['go']['main']['App']['CancelInstall'](arg1);
+}
+
 export function CheckAdmin() {
   return window['go']['main']['App']['CheckAdmin']();
 }
@@ -14,14 +18,58 @@ export function CheckWSL() {
   return window['go']['main']['App']['CheckWSL']();
 }
 
+export function ClearFinishedInstallJobs() {
+  return window['go']['main']['App']['ClearFinishedInstallJobs']();
+}
+
+export function ExportCommandHistory(arg1) {
+  return window['go']['main']['App']['ExportCommandHistory'](arg1);
+}
+
+export function ExportMetricsHistory(arg1, arg2) {
+  return window['go']['main']['App']['ExportMetricsHistory'](arg1, arg2);
+}
+
+export function GetCatalog() {
+  return window['go']['main']['App']['GetCatalog']();
+}
+
+export function GetCommandHistory(arg1) {
+  return window['go']['main']['App']['GetCommandHistory'](arg1);
+}
+
 export function GetDistroStats() {
   return window['go']['main']['App']['GetDistroStats']();
 }
 
+export function GetImageCache() {
+  return window['go']['main']['App']['GetImageCache']();
+}
+
+export function GetInstallParallelism() {
+  return window['go']['main']['App']['GetInstallParallelism']();
+}
+
+export function GetInstallProgress(arg1) {
+  return window['go']['main']['App']['GetInstallProgress'](arg1);
+}
+
 export function GetMetrics(arg1) {
   return window['go']['main']['App']['GetMetrics'](arg1);
 }
 
+export function GetMetricsHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetMetricsHistory'](arg1, arg2);
+}
+
+export function GetMetricsInterval() {
+  return window['go']['main']['App']['GetMetricsInterval']();
+}
+
+export function GetNetworkSettings() {
+  return window['go']['main']['App']['GetNetworkSettings']();
+}
+
 export function GetPath(arg1) {
   return window['go']['main']['App']['GetPath'](arg1);
 }
@@ -30,26 +78,82 @@ export function GetPerformanceConfig() {
   return window['go']['main']['App']['GetPerformanceConfig']();
 }
 
+export function GetProcesses(arg1, arg2) {
+  return window['go']['main']['App']['GetProcesses'](arg1, arg2);
+}
+
 export function GetWSLVersion() {
   return window['go']['main']['App']['GetWSLVersion']();
 }
 
-export function Install_Bottom(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['Install_Bottom'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function InspectNetwork(arg1) {
+  return window['go']['main']['App']['InspectNetwork'](arg1);
+}
+
+export function InstallFromFile(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['InstallFromFile'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function Install_Bottom(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['Install_Bottom'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function KillProcess(arg1, arg2, arg3) {
+  return window['go']['main']['App']['KillProcess'](arg1, arg2, arg3);
+}
+
+export function ListInstallJobs() {
+  return window['go']['main']['App']['ListInstallJobs']();
 }
 
 export function OpenDistroFolder(arg1) {
   return window['go']['main']['App']['OpenDistroFolder'](arg1);
 }
 
-export function SavePerformanceConfig(arg1) {
-  return window['go']['main']['App']['SavePerformanceConfig'](arg1);
+export function PurgeImageCache() {
+  return window['go']['main']['App']['PurgeImageCache']();
+}
+
+export function ReloadCatalog() {
+  return window['go']['main']['App']['ReloadCatalog']();
+}
+
+export function ReorderInstallJob(arg1, arg2) {
+  return window['go']['main']['App']['ReorderInstallJob'](arg1, arg2);
+}
+
+export function SaveNetworkSettings(arg1) {
+  return window['go']['main']['App']['SaveNetworkSettings'](arg1);
+}
+
+export function SavePerformanceConfig(arg1, arg2) {
+  return window['go']['main']['App']['SavePerformanceConfig'](arg1, arg2);
 }
 
 export function SelectDirectory() {
   return window['go']['main']['App']['SelectDirectory']();
 }
 
+export function SelectImageFile() {
+  return window['go']['main']['App']['SelectImageFile']();
+}
+
+export function SetCatalogURL(arg1) {
+  return window['go']['main']['App']['SetCatalogURL'](arg1);
+}
+
+export function SetInstallParallelism(arg1) {
+  return window['go']['main']['App']['SetInstallParallelism'](arg1);
+}
+
+export function SetMetricsInterval(arg1) {
+  return window['go']['main']['App']['SetMetricsInterval'](arg1);
+}
+
+export function SetPreferredMirror(arg1) {
+  return window['go']['main']['App']['SetPreferredMirror'](arg1);
+}
+
 export function ShowWSLInfo() {
   return window['go']['main']['App']['ShowWSLInfo']();
 }
@@ -58,10 +162,18 @@ export function StartDistro(arg1) {
   return window['go']['main']['App']['StartDistro'](arg1);
 }
 
-export function StartMigration(arg1) {
-  return window['go']['main']['App']['StartMigration'](arg1);
+export function StartMigration(arg1, arg2) {
+  return window['go']['main']['App']['StartMigration'](arg1, arg2);
+}
+
+export function SubscribeMetrics(arg1) {
+  return window['go']['main']['App']['SubscribeMetrics'](arg1);
+}
+
+export function UninstallDistro(arg1, arg2) {
+  return window['go']['main']['App']['UninstallDistro'](arg1, arg2);
 }
 
-export function UninstallDistro(arg1) {
-  return window['go']['main']['App']['UninstallDistro'](arg1);
+export function UnsubscribeMetrics(arg1) {
+  return window['go']['main']['App']['UnsubscribeMetrics'](arg1);
 }
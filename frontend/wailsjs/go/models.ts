@@ -0,0 +1,887 @@
+export namespace audit {
+	
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    program: string;
+	    args: string[];
+	    exitCode: number;
+	    durationMs: number;
+	    output: string;
+	    error: string;
+	    initiator: string;
+	    readOnly?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.program = source["program"];
+	        this.args = source["args"];
+	        this.exitCode = source["exitCode"];
+	        this.durationMs = source["durationMs"];
+	        this.output = source["output"];
+	        this.error = source["error"];
+	        this.initiator = source["initiator"];
+	        this.readOnly = source["readOnly"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Filter {
+	    query: string;
+	    program: string;
+	    initiator: string;
+	    // Go type: time
+	    since: any;
+	    // Go type: time
+	    until: any;
+	    failedOnly: boolean;
+	    hideReadOnly: boolean;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Filter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.program = source["program"];
+	        this.initiator = source["initiator"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	        this.failedOnly = source["failedOnly"];
+	        this.hideReadOnly = source["hideReadOnly"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace catalog {
+	
+	export class Provision {
+	    adminGroup?: string;
+	    installSudo?: string[];
+	    shells?: string[];
+	    sudoers?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Provision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.adminGroup = source["adminGroup"];
+	        this.installSudo = source["installSudo"];
+	        this.shells = source["shells"];
+	        this.sudoers = source["sudoers"];
+	    }
+	}
+	export class Distro {
+	    name: string;
+	    family: string;
+	    label: string;
+	    version: string;
+	    arch: string;
+	    urls: string[];
+	    sha256: string;
+	    size: number;
+	    icon: string;
+	    description: string;
+	    defaultUser: string;
+	    experimental: boolean;
+	    provision?: Provision;
+	
+	    static createFrom(source: any = {}) {
+	        return new Distro(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.family = source["family"];
+	        this.label = source["label"];
+	        this.version = source["version"];
+	        this.arch = source["arch"];
+	        this.urls = source["urls"];
+	        this.sha256 = source["sha256"];
+	        this.size = source["size"];
+	        this.icon = source["icon"];
+	        this.description = source["description"];
+	        this.defaultUser = source["defaultUser"];
+	        this.experimental = source["experimental"];
+	        this.provision = this.convertValues(source["provision"], Provision);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Catalog {
+	    schemaVersion: number;
+	    revision: string;
+	    distros: Distro[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Catalog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.schemaVersion = source["schemaVersion"];
+	        this.revision = source["revision"];
+	        this.distros = this.convertValues(source["distros"], Distro);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace installWSL {
+	
+	export enum Phase {
+	    Checking = "checking",
+	    Downloading = "downloading",
+	    Verifying = "verifying",
+	    Importing = "importing",
+	    ConfiguringUser = "configuring-user",
+	    Done = "done",
+	    Failed = "failed",
+	    Cancelled = "cancelled",
+	}
+	export enum JobStatus {
+	    Queued = "queued",
+	    Running = "running",
+	    Done = "done",
+	    Failed = "failed",
+	    Cancelled = "cancelled",
+	}
+	export enum StepKind {
+	    Command = "command",
+	    FileWrite = "file-write",
+	    FileDelete = "file-delete",
+	    RegistryRead = "registry-read",
+	    Download = "download",
+	    Wait = "wait",
+	}
+	export class CacheEntry {
+	    sha256: string;
+	    name: string;
+	    size: number;
+	    // Go type: time
+	    lastUsed: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sha256 = source["sha256"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheSummary {
+	    dir: string;
+	    totalSize: number;
+	    maxBytes: number;
+	    entries: CacheEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.totalSize = source["totalSize"];
+	        this.maxBytes = source["maxBytes"];
+	        this.entries = this.convertValues(source["entries"], CacheEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobInfo {
+	    id: string;
+	    kind: string;
+	    distro: string;
+	    status: JobStatus;
+	    phase: Phase;
+	    error: string;
+	    position: number;
+	    // Go type: time
+	    created: any;
+	    // Go type: time
+	    started: any;
+	    // Go type: time
+	    finished: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.distro = source["distro"];
+	        this.status = source["status"];
+	        this.phase = source["phase"];
+	        this.error = source["error"];
+	        this.position = source["position"];
+	        this.created = this.convertValues(source["created"], null);
+	        this.started = this.convertValues(source["started"], null);
+	        this.finished = this.convertValues(source["finished"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlanStep {
+	    kind: StepKind;
+	    description: string;
+	    command: string[];
+	    path: string;
+	    size: number;
+	    condition: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlanStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.description = source["description"];
+	        this.command = source["command"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.condition = source["condition"];
+	    }
+	}
+	export class Plan {
+	    operation: string;
+	    distro: string;
+	    steps: PlanStep[];
+	    notes: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Plan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operation = source["operation"];
+	        this.distro = source["distro"];
+	        this.steps = this.convertValues(source["steps"], PlanStep);
+	        this.notes = source["notes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Progress {
+	    jobId: string;
+	    distro: string;
+	    phase: Phase;
+	    bytesDone: number;
+	    bytesTotal: number;
+	    speed: number;
+	    eta: number;
+	    messageKey: string;
+	    params: Record<string, string>;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Progress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.distro = source["distro"];
+	        this.phase = source["phase"];
+	        this.bytesDone = source["bytesDone"];
+	        this.bytesTotal = source["bytesTotal"];
+	        this.speed = source["speed"];
+	        this.eta = source["eta"];
+	        this.messageKey = source["messageKey"];
+	        this.params = source["params"];
+	        this.error = source["error"];
+	    }
+	}
+
+}
+
+export namespace main {
+	
+	export class InstallResult {
+	    jobId: string;
+	    plan?: installWSL.Plan;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstallResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.plan = this.convertValues(source["plan"], installWSL.Plan);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MigrationOptions {
+	    sourcePath: string;
+	    targetPath: string;
+	    distroName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MigrationOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sourcePath = source["sourcePath"];
+	        this.targetPath = source["targetPath"];
+	        this.distroName = source["distroName"];
+	    }
+	}
+
+}
+
+export namespace network {
+	
+	export class Settings {
+	    proxyUrl: string;
+	    proxyUser: string;
+	    proxyPassword: string;
+	    proxyPasswordSet: boolean;
+	    noProxy: string;
+	    caBundle: string;
+	    timeoutSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.proxyUrl = source["proxyUrl"];
+	        this.proxyUser = source["proxyUser"];
+	        this.proxyPassword = source["proxyPassword"];
+	        this.proxyPasswordSet = source["proxyPasswordSet"];
+	        this.noProxy = source["noProxy"];
+	        this.caBundle = source["caBundle"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	    }
+	}
+
+}
+
+export namespace runtimeGUI {
+	
+	export enum MetricsRange {
+	    FiveMinutes = "5m",
+	    OneHour = "1h",
+	    OneDay = "24h",
+	}
+	export enum ProcessSort {
+	    CPU = "cpu",
+	    RSS = "rss",
+	    PID = "pid",
+	    User = "user",
+	    Start = "start",
+	    Command = "command",
+	}
+	export enum ProcessSignal {
+	    TERM = "TERM",
+	    KILL = "KILL",
+	    INT = "INT",
+	    HUP = "HUP",
+	    STOP = "STOP",
+	    CONT = "CONT",
+	}
+	export class List {
+	    name: string;
+	    status: string;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new List(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.version = source["version"];
+	    }
+	}
+	export class ListenSocket {
+	    protocol: string;
+	    address: string;
+	    port: number;
+	    pid: number;
+	    process: string;
+	    reachable: boolean;
+	    reason: string;
+	    windowsAddress: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ListenSocket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocol = source["protocol"];
+	        this.address = source["address"];
+	        this.port = source["port"];
+	        this.pid = source["pid"];
+	        this.process = source["process"];
+	        this.reachable = source["reachable"];
+	        this.reason = source["reason"];
+	        this.windowsAddress = source["windowsAddress"];
+	    }
+	}
+	export class MemoryStats {
+	    total: number;
+	    used: number;
+	    anon: number;
+	    cache: number;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.used = source["used"];
+	        this.anon = source["anon"];
+	        this.cache = source["cache"];
+	        this.source = source["source"];
+	    }
+	}
+	export class Metrics {
+	    cpu: string;
+	    memUsed: string;
+	    memTotal: string;
+	    usedBytes: number;
+	    totalBytes: number;
+	    disk: string;
+	    vm: MemoryStats;
+	    distro: MemoryStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new Metrics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cpu = source["cpu"];
+	        this.memUsed = source["memUsed"];
+	        this.memTotal = source["memTotal"];
+	        this.usedBytes = source["usedBytes"];
+	        this.totalBytes = source["totalBytes"];
+	        this.disk = source["disk"];
+	        this.vm = this.convertValues(source["vm"], MemoryStats);
+	        this.distro = this.convertValues(source["distro"], MemoryStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MetricsPoint {
+	    time: number;
+	    cpu: number;
+	    memUsed: number;
+	    memTotal: number;
+	    diskUsed: number;
+	    diskTotal: number;
+	    netRx: number;
+	    netTx: number;
+	    load1: number;
+	    load5: number;
+	    load15: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MetricsPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.cpu = source["cpu"];
+	        this.memUsed = source["memUsed"];
+	        this.memTotal = source["memTotal"];
+	        this.diskUsed = source["diskUsed"];
+	        this.diskTotal = source["diskTotal"];
+	        this.netRx = source["netRx"];
+	        this.netTx = source["netTx"];
+	        this.load1 = source["load1"];
+	        this.load5 = source["load5"];
+	        this.load15 = source["load15"];
+	    }
+	}
+	export class NetInterface {
+	    name: string;
+	    state: string;
+	    mac: string;
+	    mtu: number;
+	    addresses: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NetInterface(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.state = source["state"];
+	        this.mac = source["mac"];
+	        this.mtu = source["mtu"];
+	        this.addresses = source["addresses"];
+	    }
+	}
+	export class NetworkReport {
+	    networkMode: string;
+	    localhostForwarding: boolean;
+	    ignoredPorts: number[];
+	    interfaces: NetInterface[];
+	    sockets: ListenSocket[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NetworkReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.networkMode = source["networkMode"];
+	        this.localhostForwarding = source["localhostForwarding"];
+	        this.ignoredPorts = source["ignoredPorts"];
+	        this.interfaces = this.convertValues(source["interfaces"], NetInterface);
+	        this.sockets = this.convertValues(source["sockets"], ListenSocket);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Process {
+	    pid: number;
+	    ppid: number;
+	    user: string;
+	    command: string;
+	    cpuPercent: number;
+	    rssBytes: number;
+	    startTime: number;
+	    state: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Process(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pid = source["pid"];
+	        this.ppid = source["ppid"];
+	        this.user = source["user"];
+	        this.command = source["command"];
+	        this.cpuPercent = source["cpuPercent"];
+	        this.rssBytes = source["rssBytes"];
+	        this.startTime = source["startTime"];
+	        this.state = source["state"];
+	    }
+	}
+	export class ProcessQuery {
+	    filter: string;
+	    sortBy: ProcessSort;
+	    ascending: boolean;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filter = source["filter"];
+	        this.sortBy = source["sortBy"];
+	        this.ascending = source["ascending"];
+	        this.limit = source["limit"];
+	    }
+	}
+
+}
+
+export namespace setting {
+	
+	export class PerformanceConfig {
+	    memoryLimit: number;
+	    swap: number;
+	    swapFile: string;
+	    processorCount: number;
+	    networkMode: string;
+	    localhostForwarding: boolean;
+	    autoMemoryReclaim: string;
+	    sparseVhd: boolean;
+	    dnsTunneling: boolean;
+	    firewall: boolean;
+	    autoProxy: boolean;
+	    hostAddressLoopback: boolean;
+	    guiApplications: boolean;
+	    debugConsole: boolean;
+	    kernel: string;
+	    kernelModules: string;
+	    kernelCommandLine: string;
+	    safeMode: boolean;
+	    maxCrashDumpCount: number;
+	    nestedVirtualization: boolean;
+	    vmIdleTimeout: number;
+	    dnsProxy: boolean;
+	    defaultVhdSize: number;
+	    pageReporting: boolean;
+	    bestEffortDnsParsing: boolean;
+	    dnsTunnelingIpAddress: string;
+	    initialAutoProxyTimeout: number;
+	    ignoredPorts: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PerformanceConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.memoryLimit = source["memoryLimit"];
+	        this.swap = source["swap"];
+	        this.swapFile = source["swapFile"];
+	        this.processorCount = source["processorCount"];
+	        this.networkMode = source["networkMode"];
+	        this.localhostForwarding = source["localhostForwarding"];
+	        this.autoMemoryReclaim = source["autoMemoryReclaim"];
+	        this.sparseVhd = source["sparseVhd"];
+	        this.dnsTunneling = source["dnsTunneling"];
+	        this.firewall = source["firewall"];
+	        this.autoProxy = source["autoProxy"];
+	        this.hostAddressLoopback = source["hostAddressLoopback"];
+	        this.guiApplications = source["guiApplications"];
+	        this.debugConsole = source["debugConsole"];
+	        this.kernel = source["kernel"];
+	        this.kernelModules = source["kernelModules"];
+	        this.kernelCommandLine = source["kernelCommandLine"];
+	        this.safeMode = source["safeMode"];
+	        this.maxCrashDumpCount = source["maxCrashDumpCount"];
+	        this.nestedVirtualization = source["nestedVirtualization"];
+	        this.vmIdleTimeout = source["vmIdleTimeout"];
+	        this.dnsProxy = source["dnsProxy"];
+	        this.defaultVhdSize = source["defaultVhdSize"];
+	        this.pageReporting = source["pageReporting"];
+	        this.bestEffortDnsParsing = source["bestEffortDnsParsing"];
+	        this.dnsTunnelingIpAddress = source["dnsTunnelingIpAddress"];
+	        this.initialAutoProxyTimeout = source["initialAutoProxyTimeout"];
+	        this.ignoredPorts = source["ignoredPorts"];
+	    }
+	}
+
+}
+
+export namespace wslcli {
+	
+	export enum ErrorCategory {
+	    Unknown = "unknown",
+	    NotFound = "not-found",
+	    AlreadyExists = "already-exists",
+	    NeedsElevation = "needs-elevation",
+	    VirtualizationDisabled = "virtualization-disabled",
+	    DiskFull = "disk-full",
+	    AccessDenied = "access-denied",
+	    KernelMissing = "kernel-missing",
+	    Timeout = "timeout",
+	}
+
+}
+
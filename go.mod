@@ -3,9 +3,10 @@ module Golang-WSL-GUI
 go 1.24.0
 
 require (
+	github.com/ulikunitz/xz v0.5.17
 	github.com/wailsapp/wails/v2 v2.11.0
+	golang.org/x/net v0.35.0
 	golang.org/x/sys v0.40.0
-	golang.org/x/text v0.33.0
 )
 
 require (
@@ -33,7 +34,7 @@ require (
 	github.com/wailsapp/go-webview2 v1.0.22 // indirect
 	github.com/wailsapp/mimetype v1.4.1 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
-	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
 )
 
 // replace github.com/wailsapp/wails/v2 v2.11.0 => /home/ubuntu12738/go/pkg/mod
@@ -2,6 +2,9 @@ package main
 
 import (
 	start "Golang-WSL-GUI/src/Start"
+	"Golang-WSL-GUI/src/installWSL"
+	runtimeGUI "Golang-WSL-GUI/src/runtimeGUI"
+	"Golang-WSL-GUI/src/wslcli"
 	"embed"
 
 	"github.com/wailsapp/wails/v2"
@@ -26,9 +29,20 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
+		ErrorFormatter:   formatError,
 		Bind: []interface{}{
 			app,
 		},
+		EnumBind: []interface{}{
+			installWSL.AllPhases,
+			installWSL.AllJobStatuses,
+			wslcli.AllErrorCategories,
+			installWSL.AllStepKinds,
+			runtimeGUI.AllMetricsRanges,
+			runtimeGUI.AllProcessSorts,
+			runtimeGUI.AllProcessSignals,
+		},
 	})
 
 	if err != nil {
@@ -0,0 +1,301 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	logName = "commands.jsonl"
+	// 单个日志文件上限,超过后轮转
+	defaultMaxBytes = 2 << 20
+	// 保留的历史文件数
+	defaultMaxFiles = 5
+	// 每条记录保留的输出长度
+	maxOutput = 4 << 10
+)
+
+// 一条命令执行记录
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Program string    `json:"program"`
+	// 已脱敏的参数
+	Args       []string `json:"args"`
+	ExitCode   int      `json:"exitCode"`
+	DurationMs int64    `json:"durationMs"`
+	// 截断后的输出
+	Output string `json:"output"`
+	Error  string `json:"error"`
+	// 发起命令的绑定方法或任务
+	Initiator string `json:"initiator"`
+	// 只读查询,如列出发行版、读取指标与进程
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// 历史查询条件,零值字段不参与过滤
+type Filter struct {
+	// 在程序名、参数与输出中搜索
+	Query     string    `json:"query"`
+	Program   string    `json:"program"`
+	Initiator string    `json:"initiator"`
+	Since     time.Time `json:"since"`
+	Until     time.Time `json:"until"`
+	// 只返回失败的命令
+	FailedOnly bool `json:"failedOnly"`
+	// 不返回成功的只读查询,失败的仍返回
+	HideReadOnly bool `json:"hideReadOnly"`
+	// 最多返回条数,按时间从新到旧
+	Limit int `json:"limit"`
+}
+
+// 按大小轮转的JSONL审计日志
+type Log struct {
+	Dir      string
+	MaxBytes int64
+	MaxFiles int
+
+	mu sync.Mutex
+}
+
+var Default = NewLog(defaultLogDir())
+
+func NewLog(dir string) *Log {
+	return &Log{Dir: dir, MaxBytes: defaultMaxBytes, MaxFiles: defaultMaxFiles}
+}
+
+// 默认日志目录 %AppData%\Easy-WSL-GUI\logs
+func defaultLogDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "Easy-WSL-GUI", "logs")
+}
+
+type initiatorKey struct{}
+
+// 标记ctx中命令的发起者
+func WithInitiator(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, initiatorKey{}, name)
+}
+
+// 取出发起者,未标记时为空
+func InitiatorFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	name, _ := ctx.Value(initiatorKey{}).(string)
+	return name
+}
+
+// 追加一条记录,参数与输出在写入前脱敏并截断
+func (l *Log) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Args = Redact(e.Args)
+	e.Output = truncate(RedactText(e.Output), maxOutput)
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(l.Dir, 0755); err != nil {
+		return err
+	}
+	path := l.path(0)
+	if info, err := os.Stat(path); err == nil && info.Size()+int64(len(line)) > l.MaxBytes {
+		l.rotateLocked()
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// commands.jsonl -> commands.1.jsonl -> ... ,超出数量的删除
+func (l *Log) rotateLocked() {
+	os.Remove(l.path(l.MaxFiles))
+	for i := l.MaxFiles - 1; i >= 0; i-- {
+		os.Rename(l.path(i), l.path(i+1))
+	}
+}
+
+func (l *Log) path(index int) string {
+	if index == 0 {
+		return filepath.Join(l.Dir, logName)
+	}
+	return filepath.Join(l.Dir, fmt.Sprintf("commands.%d.jsonl", index))
+}
+
+// 按条件查询历史,从新到旧
+func (l *Log) Query(f Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []Entry
+	for i := 0; i <= l.MaxFiles; i++ {
+		file, err := os.Open(l.path(i))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64<<10), 1<<20)
+		for scanner.Scan() {
+			var e Entry
+			// 跳过损坏的行
+			if json.Unmarshal(scanner.Bytes(), &e) == nil && f.match(e) {
+				entries = append(entries, e)
+			}
+		}
+		file.Close()
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	if f.Limit > 0 && len(entries) > f.Limit {
+		entries = entries[:f.Limit]
+	}
+	return entries, nil
+}
+
+func (f Filter) match(e Entry) bool {
+	if f.Program != "" && !strings.EqualFold(f.Program, e.Program) {
+		return false
+	}
+	if f.Initiator != "" && !strings.EqualFold(f.Initiator, e.Initiator) {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	failed := e.ExitCode != 0 || e.Error != ""
+	if f.FailedOnly && !failed {
+		return false
+	}
+	if f.HideReadOnly && e.ReadOnly && !failed {
+		return false
+	}
+	if f.Query != "" {
+		text := strings.ToLower(e.Program + " " + strings.Join(e.Args, " ") + " " + e.Output + " " + e.Error)
+		if !strings.Contains(text, strings.ToLower(f.Query)) {
+			return false
+		}
+	}
+	return true
+}
+
+// 导出查询结果,扩展名为 .csv 时导出CSV,否则为JSONL
+func (l *Log) Export(path string, f Filter) error {
+	entries, err := l.Query(f)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		w := csv.NewWriter(file)
+		w.Write([]string{"time", "initiator", "program", "args", "exitCode", "durationMs", "error", "output"})
+		for _, e := range entries {
+			w.Write([]string{
+				e.Time.Format(time.RFC3339),
+				e.Initiator,
+				e.Program,
+				strings.Join(e.Args, " "),
+				strconv.Itoa(e.ExitCode),
+				strconv.FormatInt(e.DurationMs, 10),
+				e.Error,
+				e.Output,
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	enc := json.NewEncoder(file)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 执行并记录本地命令,用于 wsl.exe 之外的程序
+func Run(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	exitCode := 0
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	} else if err != nil {
+		exitCode = -1
+	}
+	record(ctx, cmd, start, exitCode, string(out), err)
+	return out, err
+}
+
+// 启动并记录不等待结束的本地命令,如打开资源管理器
+func Start(ctx context.Context, cmd *exec.Cmd) error {
+	err := cmd.Start()
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+	}
+	record(ctx, cmd, time.Now(), exitCode, "", err)
+	return err
+}
+
+func record(ctx context.Context, cmd *exec.Cmd, start time.Time, exitCode int, output string, err error) {
+	e := Entry{
+		Time:       start,
+		Program:    filepath.Base(cmd.Path),
+		ExitCode:   exitCode,
+		DurationMs: time.Since(start).Milliseconds(),
+		Output:     output,
+		Initiator:  InitiatorFrom(ctx),
+	}
+	if len(cmd.Args) > 1 {
+		e.Args = cmd.Args[1:]
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	Default.Record(e)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// 避免截断在多字节字符中间
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n] + "...(truncated)"
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redacted = "***"
+
+var (
+	// password=xxx、token: xxx
+	secretAssign = regexp.MustCompile(`(?i)((?:password|passwd|pwd|token|secret|api_?key)\s*[=:]\s*)[^\s'"&]+`)
+	// 代理等URL中的 user:pass@
+	urlUserinfo = regexp.MustCompile(`(://[^/\s:@]+:)[^@\s/]+@`)
+	// echo 'user:pass' | chpasswd
+	chpasswdPair = regexp.MustCompile(`([\w.-]+):[^\s'"]+(['"]?\s*\|\s*chpasswd)`)
+	// sh -c "useradd -p xxx user"
+	userPassFlag = regexp.MustCompile(`((?:useradd|usermod)\b[^|;&]*?\s-p\s+)\S+`)
+)
+
+// 跟随口令参数的选项,仅在对应命令中生效
+var secretFlags = map[string][]string{
+	"":        {"--password"},
+	"useradd": {"-p"},
+	"usermod": {"-p"},
+}
+
+// 脱敏命令参数,返回新切片
+func Redact(args []string) []string {
+	if args == nil {
+		return nil
+	}
+	out := make([]string, len(args))
+	command := ""
+	for i, arg := range args {
+		if i > 0 && isSecretFlag(command, args[i-1]) {
+			out[i] = redacted
+			continue
+		}
+		out[i] = RedactText(arg)
+		if name := strings.ToLower(arg); name == "useradd" || name == "usermod" {
+			command = name
+		}
+	}
+	return out
+}
+
+func isSecretFlag(command, arg string) bool {
+	for _, key := range []string{"", command} {
+		for _, flag := range secretFlags[key] {
+			if arg == flag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 脱敏文本中的口令、令牌与URL凭据
+func RedactText(s string) string {
+	s = secretAssign.ReplaceAllString(s, "${1}"+redacted)
+	s = urlUserinfo.ReplaceAllString(s, "${1}"+redacted+"@")
+	s = userPassFlag.ReplaceAllString(s, "${1}"+redacted)
+	return chpasswdPair.ReplaceAllString(s, "${1}:"+redacted+"${2}")
+}
@@ -0,0 +1,307 @@
+package catalog
+
+import (
+	"context"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 当前支持的清单格式版本
+const SchemaVersion = 1
+
+// 远程清单大小上限
+const maxRemoteSize = 4 << 20
+
+// 用户配置策略,决定管理员组等差异
+const (
+	UserAuto   = "auto"
+	UserDebian = "debian"
+	UserFedora = "fedora"
+	UserArch   = "arch"
+	UserSuse   = "suse"
+	UserAlpine = "alpine"
+)
+
+var (
+	groupPattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+	shellPattern = regexp.MustCompile(`^/[A-Za-z0-9._/-]+$`)
+)
+
+//go:embed DefaultCatalog.json
+var defaultCatalog []byte
+
+// 发行版条目
+type Distro struct {
+	Name         string   `json:"name"`
+	Family       string   `json:"family"`
+	Label        string   `json:"label"`
+	Version      string   `json:"version"`
+	Arch         string   `json:"arch"`
+	URLs         []string `json:"urls"`
+	Sha256       string   `json:"sha256"`
+	Size         int64    `json:"size"`
+	Icon         string   `json:"icon"`
+	Description  string   `json:"description"`
+	DefaultUser  string   `json:"defaultUser"`
+	Experimental bool     `json:"experimental"`
+	// 覆盖 defaultUser 策略中的部分设置
+	Provision *Provision `json:"provision,omitempty"`
+}
+
+// 用户配置覆盖项,未填写的字段沿用策略默认值
+type Provision struct {
+	// 管理员组,如 wheel
+	AdminGroup string `json:"adminGroup,omitempty"`
+	// 安装sudo的命令
+	InstallSudo []string `json:"installSudo,omitempty"`
+	// 按优先级排列的登录shell
+	Shells []string `json:"shells,omitempty"`
+	// 追加到 sudoers 配置中的行
+	Sudoers []string `json:"sudoers,omitempty"`
+}
+
+// 发行版清单
+type Catalog struct {
+	SchemaVersion int      `json:"schemaVersion"`
+	Revision      string   `json:"revision"`
+	Distros       []Distro `json:"distros"`
+}
+
+// 清单来源,为空的来源会被跳过
+type Sources struct {
+	OverridePath string
+	RemoteURL    string
+	Client       *http.Client
+}
+
+var (
+	mu      sync.RWMutex
+	current = mustDefault()
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func mustDefault() *Catalog {
+	c, err := Parse(defaultCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("内置发行版清单无效: %v", err))
+	}
+	return c
+}
+
+// 当前生效的清单
+func Current() *Catalog {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// 按内置清单 -> 远程清单 -> 用户覆盖文件的顺序加载并合并,
+// 单个来源失败时跳过并返回警告,内置清单始终可用
+func Load(ctx context.Context, src Sources) (*Catalog, []error) {
+	merged := mustDefault()
+	var warnings []error
+
+	if src.RemoteURL != "" {
+		remote, err := fetchRemote(ctx, src.RemoteURL, src.Client)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("远程清单 %s: %w", src.RemoteURL, err))
+		} else {
+			merged.merge(remote)
+		}
+	}
+
+	if src.OverridePath != "" {
+		data, err := os.ReadFile(src.OverridePath)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			warnings = append(warnings, fmt.Errorf("覆盖清单 %s: %w", src.OverridePath, err))
+		default:
+			override, err := Parse(data)
+			if err != nil {
+				warnings = append(warnings, fmt.Errorf("覆盖清单 %s: %w", src.OverridePath, err))
+			} else {
+				merged.merge(override)
+			}
+		}
+	}
+
+	return merged, warnings
+}
+
+// 加载并替换当前清单
+func Reload(ctx context.Context, src Sources) []error {
+	c, warnings := Load(ctx, src)
+	mu.Lock()
+	current = c
+	mu.Unlock()
+	return warnings
+}
+
+// 解析并校验清单
+func Parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("解析清单失败: %w", err)
+	}
+	c.normalize()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// 补全可省略的字段
+func (c *Catalog) normalize() {
+	for i := range c.Distros {
+		d := &c.Distros[i]
+		d.Sha256 = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d.Sha256)), "0x")
+		if d.Label == "" {
+			d.Label = d.Name
+		}
+		if d.Family == "" {
+			d.Family = d.Name
+		}
+		if d.Arch == "" {
+			d.Arch = "x86_64"
+		}
+		if d.DefaultUser == "" {
+			d.DefaultUser = UserAuto
+		}
+	}
+}
+
+// 校验清单格式,返回所有问题
+func (c *Catalog) Validate() error {
+	var errs []error
+	if c.SchemaVersion != SchemaVersion {
+		errs = append(errs, fmt.Errorf("不支持的清单版本 %d", c.SchemaVersion))
+	}
+
+	seen := map[string]bool{}
+	for i, d := range c.Distros {
+		where := fmt.Sprintf("distros[%d] %s", i, d.Name)
+		if !namePattern.MatchString(d.Name) {
+			errs = append(errs, fmt.Errorf("%s: 名称无效", where))
+		}
+		key := strings.ToLower(d.Name)
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("%s: 名称重复(不区分大小写)", where))
+		}
+		seen[key] = true
+
+		if len(d.URLs) == 0 {
+			errs = append(errs, fmt.Errorf("%s: 缺少下载地址", where))
+		}
+		for _, u := range d.URLs {
+			parsed, err := url.Parse(u)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				errs = append(errs, fmt.Errorf("%s: 下载地址无效 %q", where, u))
+			}
+		}
+		if _, err := hex.DecodeString(d.Sha256); err != nil || len(d.Sha256) != 64 {
+			errs = append(errs, fmt.Errorf("%s: sha256无效", where))
+		}
+		if d.Arch != "x86_64" && d.Arch != "arm64" {
+			errs = append(errs, fmt.Errorf("%s: 不支持的架构 %q", where, d.Arch))
+		}
+		if d.Size < 0 {
+			errs = append(errs, fmt.Errorf("%s: 大小不能为负数", where))
+		}
+		switch d.DefaultUser {
+		case UserAuto, UserDebian, UserFedora, UserArch, UserSuse, UserAlpine:
+		default:
+			errs = append(errs, fmt.Errorf("%s: 未知的用户配置策略 %q", where, d.DefaultUser))
+		}
+		if p := d.Provision; p != nil {
+			if p.AdminGroup != "" && !groupPattern.MatchString(p.AdminGroup) {
+				errs = append(errs, fmt.Errorf("%s: 管理员组无效 %q", where, p.AdminGroup))
+			}
+			for _, sh := range p.Shells {
+				if !shellPattern.MatchString(sh) {
+					errs = append(errs, fmt.Errorf("%s: shell必须是绝对路径 %q", where, sh))
+				}
+			}
+			for _, line := range p.Sudoers {
+				if strings.ContainsAny(line, "\r\n") {
+					errs = append(errs, fmt.Errorf("%s: sudoers配置不能包含换行", where))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 按名称查找发行版,优先精确匹配
+func (c *Catalog) Find(name string) (Distro, bool) {
+	for _, d := range c.Distros {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	for _, d := range c.Distros {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+	return Distro{}, false
+}
+
+// 合并另一份清单,同名条目整体替换,新条目追加到末尾
+func (c *Catalog) merge(other *Catalog) {
+	if other.Revision != "" {
+		c.Revision = other.Revision
+	}
+	for _, d := range other.Distros {
+		replaced := false
+		for i := range c.Distros {
+			if strings.EqualFold(c.Distros[i].Name, d.Name) {
+				c.Distros[i] = d
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Distros = append(c.Distros, d)
+		}
+	}
+}
+
+func fetchRemote(ctx context.Context, rawURL string, client *http.Client) (*Catalog, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("网络错误码: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize))
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSha = "c74833a55e525b1e99e1541509c566bb3e32bdb53bf27ea3347174364a57f47c"
+
+func catalogJSON(revision string, distros ...string) string {
+	return `{"schemaVersion":1,"revision":"` + revision + `","distros":[` + strings.Join(distros, ",") + `]}`
+}
+
+func distroJSON(name, url string) string {
+	return `{"name":"` + name + `","urls":["` + url + `"],"sha256":"0x` + testSha + `"}`
+}
+
+func serve(t *testing.T, status int, body string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func writeOverride(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultCatalogValid(t *testing.T) {
+	c := mustDefault()
+	if len(c.Distros) == 0 {
+		t.Fatal("内置清单为空")
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 内置 -> 远程 -> 覆盖文件,后者同名条目替换前者,新条目追加在末尾
+func TestLoadMergeOrder(t *testing.T) {
+	builtin := mustDefault()
+	first := builtin.Distros[0].Name
+
+	remote := serve(t, http.StatusOK, catalogJSON("r2",
+		distroJSON(first, "https://remote.example/a.wsl"),
+		distroJSON("RemoteOnly", "https://remote.example/b.wsl"),
+	))
+	override := writeOverride(t, catalogJSON("",
+		distroJSON("remoteonly", "https://override.example/b.wsl"),
+		distroJSON("Mine", "https://override.example/c.tar"),
+	))
+
+	c, warnings := Load(context.Background(), Sources{RemoteURL: remote, OverridePath: override})
+	if len(warnings) != 0 {
+		t.Fatal(warnings)
+	}
+	if c.Revision != "r2" {
+		t.Fatalf("revision = %q", c.Revision)
+	}
+	if len(c.Distros) != len(builtin.Distros)+2 {
+		t.Fatalf("条目数 = %d", len(c.Distros))
+	}
+	if d := c.Distros[0]; d.Name != first || d.URLs[0] != "https://remote.example/a.wsl" {
+		t.Fatalf("远程清单应原位替换内置条目: %+v", d)
+	}
+	// 覆盖文件按名称不区分大小写替换远程条目
+	if d, _ := c.Find("RemoteOnly"); d.URLs[0] != "https://override.example/b.wsl" {
+		t.Fatalf("覆盖文件未生效: %+v", d)
+	}
+	if last := c.Distros[len(c.Distros)-1]; last.Name != "Mine" {
+		t.Fatalf("新条目应追加在末尾: %+v", last)
+	}
+	// 内置清单本身不受合并影响
+	if mustDefault().Distros[0].URLs[0] == "https://remote.example/a.wsl" {
+		t.Fatal("内置清单被修改")
+	}
+}
+
+// 远程或覆盖清单不可用时退回内置清单并给出警告
+func TestLoadFallback(t *testing.T) {
+	builtin := mustDefault()
+	cases := []struct {
+		name     string
+		src      Sources
+		warnings int
+	}{
+		{"remote500", Sources{RemoteURL: serve(t, http.StatusInternalServerError, "")}, 1},
+		{"remoteInvalid", Sources{RemoteURL: serve(t, http.StatusOK, `{"schemaVersion":99}`)}, 1},
+		{"overrideMissing", Sources{OverridePath: filepath.Join(t.TempDir(), "none.json")}, 0},
+		{"overrideInvalid", Sources{OverridePath: writeOverride(t, catalogJSON("", distroJSON("bad name", "ftp://x")))}, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, warnings := Load(context.Background(), c.src)
+			if len(warnings) != c.warnings {
+				t.Fatalf("warnings = %v", warnings)
+			}
+			if len(got.Distros) != len(builtin.Distros) || got.Revision != builtin.Revision {
+				t.Fatal("应退回内置清单")
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	_, err := Parse([]byte(catalogJSON("", distroJSON("bad name", "ftp://x"), distroJSON("A", "https://a/b"), distroJSON("a", "https://a/c"))))
+	if err == nil {
+		t.Fatal("应校验失败")
+	}
+	for _, want := range []string{"名称无效", "下载地址无效", "名称重复"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("缺少错误 %q: %v", want, err)
+		}
+	}
+}
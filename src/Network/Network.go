@@ -0,0 +1,185 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// 未设置超时时的默认值(秒)
+const defaultTimeout = 30
+
+// 程序所有对外HTTP请求共用的网络设置
+type Settings struct {
+	// 代理地址,支持 http://、https://、socks5://,为空时使用系统环境变量
+	ProxyURL      string `json:"proxyUrl"`
+	ProxyUser     string `json:"proxyUser"`
+	ProxyPassword string `json:"proxyPassword"`
+	// 是否已保存代理密码,返回给前端时以此代替密码本身
+	ProxyPasswordSet bool `json:"proxyPasswordSet"`
+	// 不走代理的主机,逗号分隔,如 localhost,.corp.example.com,10.0.0.0/8
+	NoProxy string `json:"noProxy"`
+	// 额外信任的CA证书文件(PEM)
+	CABundle string `json:"caBundle"`
+	// 连接、TLS握手及等待响应头的超时,不限制整个下载时长
+	TimeoutSeconds int `json:"timeoutSeconds"`
+}
+
+var (
+	mu     sync.RWMutex
+	client = mustClient(Settings{})
+)
+
+func mustClient(s Settings) *http.Client {
+	c, err := NewClient(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// 当前共享的HTTP客户端
+func Client() *http.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+	return client
+}
+
+// 按设置重建共享客户端,设置无效时保持原客户端不变
+func Apply(s Settings) error {
+	c, err := NewClient(s)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	client = c
+	mu.Unlock()
+	return nil
+}
+
+// 去掉密码的副本,供前端显示
+func (s Settings) Masked() Settings {
+	s.ProxyPasswordSet = s.ProxyPassword != ""
+	s.ProxyPassword = ""
+	return s
+}
+
+// 前端未修改密码时提交的是Masked的结果,此时沿用已保存的密码
+func (s Settings) KeepPassword(stored Settings) Settings {
+	if s.ProxyPassword == "" && s.ProxyPasswordSet {
+		s.ProxyPassword = stored.ProxyPassword
+	}
+	s.ProxyPasswordSet = s.ProxyPassword != ""
+	return s
+}
+
+// 根据设置创建HTTP客户端
+func NewClient(s Settings) (*http.Client, error) {
+	proxy, err := proxyFunc(s)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := tlsConfig(s.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(s.TimeoutSeconds) * time.Second
+	if s.TimeoutSeconds <= 0 {
+		timeout = defaultTimeout * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{Transport: transport}, nil
+}
+
+// 校验设置,返回所有问题
+func (s Settings) Validate() error {
+	var errs []error
+	if _, err := proxyFunc(s); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := tlsConfig(s.CABundle); err != nil {
+		errs = append(errs, err)
+	}
+	if s.TimeoutSeconds < 0 {
+		errs = append(errs, errors.New("超时时间不能为负数"))
+	}
+	return errors.Join(errs...)
+}
+
+// 未配置代理时沿用环境变量,否则http与https请求都走该代理,NoProxy中的主机直连
+func proxyFunc(s Settings) (func(*http.Request) (*url.URL, error), error) {
+	raw := strings.TrimSpace(s.ProxyURL)
+	if raw == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("代理地址无效: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("不支持的代理协议 %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.New("代理地址缺少主机名")
+	}
+	if s.ProxyUser != "" {
+		proxyURL.User = url.UserPassword(s.ProxyUser, s.ProxyPassword)
+	}
+
+	cfg := &httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    s.NoProxy,
+	}
+	match := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return match(req.URL)
+	}, nil
+}
+
+// 在系统证书的基础上追加自定义CA
+func tlsConfig(bundle string) (*tls.Config, error) {
+	bundle = strings.TrimSpace(bundle)
+	if bundle == "" {
+		return nil, nil
+	}
+
+	pem, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("读取CA证书失败: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA证书文件 %s 中没有有效的PEM证书", bundle)
+	}
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
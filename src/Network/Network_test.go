@@ -0,0 +1,107 @@
+package network
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientProxy(t *testing.T) {
+	var auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Proxy-Authorization")
+		io.WriteString(w, "via-proxy")
+	}))
+	defer proxy.Close()
+
+	c, err := NewClient(Settings{ProxyURL: proxy.URL, ProxyUser: "u", ProxyPassword: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Get("http://example.invalid/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "via-proxy" {
+		t.Fatalf("响应 = %q, 请求没有经过代理", body)
+	}
+	// u:p 的Basic认证
+	if auth != "Basic dTpw" {
+		t.Fatalf("Proxy-Authorization = %q", auth)
+	}
+}
+
+func TestClientCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "ok") }))
+	defer srv.Close()
+
+	if _, err := mustClient(Settings{}).Get(srv.URL); err == nil {
+		t.Fatal("未信任的证书应校验失败")
+	}
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(Settings{CABundle: ca})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       Settings
+		wantErr bool
+	}{
+		{"empty", Settings{}, false},
+		{"socks5", Settings{ProxyURL: "socks5://127.0.0.1:1080"}, false},
+		{"noScheme", Settings{ProxyURL: "127.0.0.1:8080"}, false},
+		{"ftp", Settings{ProxyURL: "ftp://x"}, true},
+		{"missingCA", Settings{CABundle: filepath.Join(t.TempDir(), "none.pem")}, true},
+		{"negativeTimeout", Settings{TimeoutSeconds: -1}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.s.Validate(); (err != nil) != c.wantErr {
+				t.Fatalf("err = %v", err)
+			}
+		})
+	}
+}
+
+func TestMaskedPassword(t *testing.T) {
+	stored := Settings{ProxyURL: "http://proxy:8080", ProxyUser: "u", ProxyPassword: "secret"}
+	masked := stored.Masked()
+	if masked.ProxyPassword != "" || !masked.ProxyPasswordSet {
+		t.Fatalf("Masked = %+v", masked)
+	}
+
+	// 前端原样提交时沿用已保存的密码
+	if got := masked.KeepPassword(stored); got.ProxyPassword != "secret" {
+		t.Fatalf("密码 = %q, 期望沿用已保存的密码", got.ProxyPassword)
+	}
+	// 输入新密码
+	changed := masked
+	changed.ProxyPassword = "new"
+	if got := changed.KeepPassword(stored); got.ProxyPassword != "new" {
+		t.Fatalf("密码 = %q, 期望 new", got.ProxyPassword)
+	}
+	// 清除密码
+	cleared := masked
+	cleared.ProxyPasswordSet = false
+	if got := cleared.KeepPassword(stored); got.ProxyPassword != "" || got.ProxyPasswordSet {
+		t.Fatalf("清除后 = %+v", got)
+	}
+}
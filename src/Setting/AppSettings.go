@@ -0,0 +1,83 @@
+package setting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	network "Golang-WSL-GUI/src/Network"
+)
+
+// 程序自身设置,保存在 %AppData%\Easy-WSL-GUI\settings.json
+type AppSettings struct {
+	CatalogURL string `json:"catalogUrl"`
+	// 首选下载镜像主机关键字,为空时自动选择最快的镜像
+	PreferredMirror string `json:"preferredMirror"`
+	// 同时安装的任务数上限,0为默认值
+	InstallParallelism int `json:"installParallelism"`
+	// 发行版指标的采样间隔秒数,0为默认值
+	MetricsInterval int `json:"metricsInterval"`
+	// 代理与证书设置,下载器和清单更新共用
+	Network network.Settings `json:"network"`
+}
+
+var settingsMu sync.Mutex
+
+// 程序配置目录
+func AppConfigDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "Easy-WSL-GUI")
+}
+
+// 用户自定义发行版清单路径
+func CatalogOverridePath() string {
+	return filepath.Join(AppConfigDir(), "catalog.json")
+}
+
+func appSettingsPath() string {
+	return filepath.Join(AppConfigDir(), "settings.json")
+}
+
+// 读取程序设置,文件不存在或损坏时返回默认值
+func LoadAppSettings() AppSettings {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+
+	var s AppSettings
+	data, err := os.ReadFile(appSettingsPath())
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		fmt.Printf("读取程序设置失败: %v\n", err)
+		return AppSettings{}
+	}
+	return s
+}
+
+// 保存程序设置
+func SaveAppSettings(s AppSettings) error {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+
+	if err := os.MkdirAll(AppConfigDir(), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := appSettingsPath()
+	tmp := path + ".tmp"
+	// 含代理密码,仅当前用户可读
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("写入程序设置失败: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -44,13 +44,38 @@ type PerformanceConfig struct {
 }
 
 func Wriding_PerformanceConfig(config PerformanceConfig) error {
-	userHome, err := os.UserHomeDir()
+	configFile, err := performanceConfigPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(configFile, []byte(renderPerformanceConfig(config)), 0644)
+	if err != nil {
+		return fmt.Errorf("无法写入.wslconfig,错误码: %v", err)
+	}
+	return nil
+}
+
+// 预演写入: 返回将写入的文件路径与字节数,不修改文件
+func PlanPerformanceConfig(config PerformanceConfig) (string, int64, error) {
+	configFile, err := performanceConfigPath()
 	if err != nil {
-		return fmt.Errorf("无法打开用户文件夹: %v", err)
+		return "", 0, err
 	}
+	return configFile, int64(len(renderPerformanceConfig(config))), nil
+}
 
-	configFile := filepath.Join(userHome, ".wslconfig")
+// .wslconfig 路径
+func performanceConfigPath() (string, error) {
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("无法打开用户文件夹: %v", err)
+	}
+	return filepath.Join(userHome, ".wslconfig"), nil
+}
 
+// 生成 .wslconfig 内容
+func renderPerformanceConfig(config PerformanceConfig) string {
 	content := fmt.Sprintf(`[wsl2]
 memory=%dGB
 swap=%dGB
@@ -114,12 +139,7 @@ hostAddressLoopback=%v
 	if config.IgnoredPorts != "" {
 		content += fmt.Sprintf("ignoredPorts=%s\n", config.IgnoredPorts)
 	}
-
-	err = os.WriteFile(configFile, []byte(content), 0644)
-	if err != nil {
-		return fmt.Errorf("无法写入.wslconfig,错误码: %v", err)
-	}
-	return nil
+	return content
 }
 
 func Rading_PerformanceConfig() PerformanceConfig {
@@ -37,6 +37,9 @@ type PerformanceConfig struct {
 // 写入.wslconfig函数
 func Wriding_PerformanceConfig(config PerformanceConfig) error { return nil }
 
+// 预演写入.wslconfig
+func PlanPerformanceConfig(config PerformanceConfig) (string, int64, error) { return "", 0, nil }
+
 // 读取.wslconfig函数
 func Rading_PerformanceConfig() PerformanceConfig {
 	config := PerformanceConfig{
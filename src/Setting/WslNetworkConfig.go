@@ -0,0 +1,84 @@
+package setting
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// .wslconfig 中影响端口能否从Windows访问的设置
+type WslNetworkConfig struct {
+	NetworkingMode      string
+	LocalhostForwarding bool
+	// 逗号分隔,仅镜像模式生效
+	IgnoredPorts string
+}
+
+// 未配置时WSL自身的默认值,与界面中的推荐值无关
+func DefaultWslNetworkConfig() WslNetworkConfig {
+	return WslNetworkConfig{NetworkingMode: "nat", LocalhostForwarding: true}
+}
+
+// 解析 .wslconfig 的 [wsl2] 段;旧版本写在 [experimental] 段的设置同样生效,[wsl2] 优先
+func ParseWslNetworkConfig(r io.Reader) (WslNetworkConfig, error) {
+	config := DefaultWslNetworkConfig()
+	section := ""
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		if section != "wsl2" && section != "experimental" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		// 去掉行尾注释,如 networkingMode=mirrored # 说明
+		if i := strings.IndexAny(value, "#;"); i >= 0 {
+			value = value[:i]
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if section == "experimental" && seen[key] {
+			continue
+		}
+		if section == "wsl2" {
+			seen[key] = true
+		}
+		switch key {
+		case "networkingmode":
+			config.NetworkingMode = strings.ToLower(value)
+		case "localhostforwarding":
+			config.LocalhostForwarding = strings.EqualFold(value, "true")
+		case "ignoredports":
+			config.IgnoredPorts = value
+		}
+	}
+	return config, scanner.Err()
+}
+
+// 读取当前用户的 .wslconfig,文件不存在时返回WSL默认值
+func ReadWslNetworkConfig() (WslNetworkConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultWslNetworkConfig(), err
+	}
+	file, err := os.Open(filepath.Join(home, ".wslconfig"))
+	if os.IsNotExist(err) {
+		return DefaultWslNetworkConfig(), nil
+	} else if err != nil {
+		return DefaultWslNetworkConfig(), err
+	}
+	defer file.Close()
+	return ParseWslNetworkConfig(file)
+}
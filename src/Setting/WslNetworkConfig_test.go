@@ -0,0 +1,42 @@
+package setting
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWslNetworkConfig(t *testing.T) {
+	cases := []struct {
+		name, text string
+		want       WslNetworkConfig
+	}{
+		// 未配置时为WSL的默认值: NAT 且开启 localhost 转发
+		{"empty", "", WslNetworkConfig{NetworkingMode: "nat", LocalhostForwarding: true}},
+		{"other sections", "[boot]\nnetworkingMode=mirrored\n[wsl2]\nmemory=8GB\n", WslNetworkConfig{NetworkingMode: "nat", LocalhostForwarding: true}},
+		{"wsl2", "# c\n[wsl2]\nnetworkingMode = Mirrored\nlocalhostForwarding=false\nignoredPorts=3000,9000\n", WslNetworkConfig{NetworkingMode: "mirrored", IgnoredPorts: "3000,9000"}},
+		{"experimental", "[experimental]\nnetworkingMode=mirrored\n", WslNetworkConfig{NetworkingMode: "mirrored", LocalhostForwarding: true}},
+		{"inline comments", "[wsl2]\nnetworkingMode=mirrored # test\nlocalhostForwarding=true ; x\nignoredPorts=\"22,80\" # ssh\n", WslNetworkConfig{NetworkingMode: "mirrored", LocalhostForwarding: true, IgnoredPorts: "22,80"}},
+		{"experimental overridden", "[experimental]\nnetworkingMode=mirrored\nlocalhostForwarding=false\n[wsl2]\nnetworkingMode=nat ; 覆盖\n", WslNetworkConfig{NetworkingMode: "nat"}},
+		{"wsl2 wins", "[wsl2]\nnetworkingMode=nat\n[experimental]\nnetworkingMode=mirrored\nignoredPorts=22\n", WslNetworkConfig{NetworkingMode: "nat", LocalhostForwarding: true, IgnoredPorts: "22"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseWslNetworkConfig(strings.NewReader(c.text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Fatalf("got %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReadWslNetworkConfigMissing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+	got, err := ReadWslNetworkConfig()
+	if err != nil || got != DefaultWslNetworkConfig() {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+}
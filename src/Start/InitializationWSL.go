@@ -4,13 +4,17 @@
 package start
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 	"unsafe"
+
+	audit "Golang-WSL-GUI/src/Audit"
 )
 
 const (
@@ -18,6 +22,8 @@ const (
 	MB_ICONERROR = 0x00000010
 )
 
+const detectTimeout = 20 * time.Second
+
 // 调用 Windows user32.dll 弹窗
 func ShowNativeMessageBox(title, text string) {
 	user32 := syscall.NewLazyDLL("user32.dll")
@@ -39,12 +45,18 @@ func ShowNativeMessageBox(title, text string) {
 }
 
 // 详细检测wsl
-func DetectWSL() error {
+func DetectWSL(ctx context.Context) error {
 	_, err := exec.LookPath("wsl.exe")
 	if err != nil {
 		return fmt.Errorf("wsl.exe not found")
 	}
-	if err := exec.Command("wsl.exe", "--status").Run(); err != nil {
+	// WSL虚拟机卡死时 --status 可能一直不返回
+	ctx, cancel := context.WithTimeout(ctx, detectTimeout)
+	defer cancel()
+	if _, err := audit.Run(ctx, exec.CommandContext(ctx, "wsl.exe", "--status")); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("wsl.exe --status timed out after %s", detectTimeout)
+		}
 		return fmt.Errorf("wsl exists but not enabled")
 	}
 	return nil
@@ -59,6 +71,8 @@ func ShowFatalError(err error) {
 		msg += "未检测到 wsl.exe。\n请在 Windows 中启用 WSL。"
 	case strings.Contains(err.Error(), "not enabled"):
 		msg += "检测到 wsl.exe，但 WSL 功能未启用。\n请以管理员身份安装。"
+	case strings.Contains(err.Error(), "timed out"):
+		msg += "WSL 长时间无响应。\n请在命令行执行 wsl --shutdown 后重新打开本程序。"
 	default:
 		msg += err.Error()
 	}
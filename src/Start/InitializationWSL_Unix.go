@@ -3,11 +3,13 @@
 
 package start
 
+import "context"
+
 // 输出错误
 func ShowFatalError(err error) {}
 
 // 详细检测wsl
-func DetectWSL() error { return nil }
+func DetectWSL(ctx context.Context) error { return nil }
 
 // 启动时生成.wslconfig文件
 func EnsureWslConfigExists() error { return nil }
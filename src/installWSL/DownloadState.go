@@ -0,0 +1,114 @@
+package installWSL
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// 断点续传状态文件后缀
+const stateSuffix = ".download.json"
+
+// 断点续传状态,保存在镜像文件旁
+type downloadState struct {
+	mu sync.Mutex
+
+	URL          string   `json:"url"`
+	Size         int64    `json:"size"`
+	ETag         string   `json:"etag,omitempty"`
+	LastModified string   `json:"lastModified,omitempty"`
+	Sha256       string   `json:"sha256"`
+	Chunks       []*chunk `json:"chunks"`
+}
+
+// 状态文件路径
+func statePath(filePath string) string {
+	return filePath + stateSuffix
+}
+
+func newState(task DownloadTask, m *mirror, chunks []*chunk) *downloadState {
+	return &downloadState{
+		URL:          m.URL,
+		Size:         m.remote.Size,
+		ETag:         m.remote.ETag,
+		LastModified: m.remote.LastModified,
+		Sha256:       normalizeSha256(task.Sha256),
+		Chunks:       chunks,
+	}
+}
+
+// 读取状态文件,与当前任务或远端文件不一致时返回nil
+// 有Sha256时以内容为准,允许换镜像续传;否则要求同一地址且校验值未变
+func loadState(path string, task DownloadTask, m *mirror) *downloadState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var state downloadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+
+	remote := m.remote
+	switch {
+	case state.Size != remote.Size,
+		state.Sha256 != normalizeSha256(task.Sha256),
+		len(state.Chunks) == 0:
+		return nil
+	case state.Sha256 == "" && (state.URL != m.URL ||
+		remote.ETag != "" && state.ETag != remote.ETag ||
+		remote.LastModified != "" && state.LastModified != remote.LastModified):
+		return nil
+	}
+
+	// 残缺文件必须还在且大小一致
+	info, err := os.Stat(task.FilePath)
+	if err != nil || info.Size() != state.Size {
+		return nil
+	}
+	return &state
+}
+
+// 写入状态文件,先写临时文件再替换
+func (s *downloadState) save(path string) error {
+	s.mu.Lock()
+	data, err := json.Marshal(s)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *downloadState) advance(c *chunk, n int64) {
+	s.mu.Lock()
+	c.Done += n
+	s.mu.Unlock()
+}
+
+func (s *downloadState) chunkDone(c *chunk) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return c.Done
+}
+
+func (s *downloadState) doneBytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for _, c := range s.Chunks {
+		total += c.Done
+	}
+	return total
+}
+
+// 删除下载文件及其续传状态
+func RemoveDownload(filePath string) {
+	os.Remove(filePath)
+	os.Remove(statePath(filePath))
+}
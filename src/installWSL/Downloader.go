@@ -0,0 +1,491 @@
+package installWSL
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	network "Golang-WSL-GUI/src/Network"
+)
+
+const (
+	// 单个分段最小字节数,文件太小时减少线程数
+	minChunkSize = 1 << 20
+	// 线程上限
+	maxDownloadThreads = 16
+	// 单个分段失败后的重试次数
+	chunkRetries = 3
+	// 进度回调间隔
+	progressInterval = 500 * time.Millisecond
+)
+
+var ErrChecksum = errors.New("Sha256校验失败")
+
+var errRemoteChanged = errors.New("远端文件已变化")
+
+// 下载任务
+type DownloadTask struct {
+	// 同一文件的多个镜像地址
+	URLs     []string
+	FilePath string
+	Sha256   string
+	Threads  int
+	Client   *http.Client
+	// 进度回调,total未知时为-1
+	Progress func(done, total int64)
+	// 检测到未完成的下载并继续时回调
+	Resume func(done, total int64)
+	// 首选镜像主机关键字,为空时按延迟选择
+	PreferredMirror string
+	// 选定或切换镜像时回调
+	Mirror func(url string)
+	// 分段下载完成、开始校验整个文件时回调
+	Verifying func()
+}
+
+// 探测到的远端文件信息
+type remoteFile struct {
+	Size         int64
+	Ranged       bool
+	ETag         string
+	LastModified string
+}
+
+// 分段信息,End为闭区间
+type chunk struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	Done  int64 `json:"done"`
+}
+
+// 下载文件到FilePath,先探测所有镜像选出最快的,
+// 服务器支持Range时按Threads分段并行下载并支持断点续传,否则退回单线程,出错时切换镜像
+func DownloadFile(ctx context.Context, task DownloadTask) error {
+	client := task.Client
+	if client == nil {
+		client = network.Client()
+	}
+
+	mirrors, err := rankMirrors(ctx, client, task.URLs, task.PreferredMirror)
+	if err != nil {
+		return err
+	}
+	if task.Mirror != nil {
+		task.Mirror(mirrors[0].URL)
+	}
+
+	var sum []byte
+	if mirrors[0].remote.Ranged {
+		threads := clampThreads(task.Threads, mirrors[0].remote.Size)
+		err = downloadRanged(ctx, client, task, rangedMirrors(mirrors), threads)
+		if errors.Is(err, errRemoteChanged) {
+			// 远端文件已变化,丢弃旧进度并重新探测后下载
+			RemoveDownload(task.FilePath)
+			if mirrors, err = rankMirrors(ctx, client, task.URLs, task.PreferredMirror); err != nil {
+				return err
+			}
+			if ranged := rangedMirrors(mirrors); len(ranged) > 0 {
+				err = downloadRanged(ctx, client, task, ranged, threads)
+			} else {
+				sum, err = downloadSingleFailover(ctx, client, task, mirrors)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if sum == nil {
+			if task.Verifying != nil {
+				task.Verifying()
+			}
+			if sum, err = hashFile(ctx, task.FilePath); err != nil {
+				return err
+			}
+		}
+	} else {
+		// 无法续传,清理可能残留的状态文件
+		os.Remove(statePath(task.FilePath))
+		if sum, err = downloadSingleFailover(ctx, client, task, mirrors); err != nil {
+			return err
+		}
+	}
+
+	return checkSha256(sum, task.Sha256)
+}
+
+// 探测文件大小以及服务器是否支持Range请求
+func probeRange(ctx context.Context, client *http.Client, url string) (remoteFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return remoteFile{}, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return remoteFile{}, err
+	}
+	defer resp.Body.Close()
+
+	remote := remoteFile{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		remote.Size = parseContentRangeTotal(resp.Header.Get("Content-Range"))
+		remote.Ranged = remote.Size > 0 && !strings.EqualFold(resp.Header.Get("Accept-Ranges"), "none")
+		return remote, nil
+	case http.StatusOK:
+		// 服务器忽略了Range头,不支持分段
+		remote.Size = resp.ContentLength
+		return remote, nil
+	default:
+		return remoteFile{}, fmt.Errorf("下载失败,网络错误码: %d", resp.StatusCode)
+	}
+}
+
+// 解析 "bytes 0-0/12345" 中的总大小
+func parseContentRangeTotal(v string) int64 {
+	i := strings.LastIndex(v, "/")
+	if i < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(v[i+1:]), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
+
+// 根据文件大小限制线程数
+func clampThreads(threads int, size int64) int {
+	if threads < 1 {
+		threads = 1
+	}
+	if threads > maxDownloadThreads {
+		threads = maxDownloadThreads
+	}
+	if size > 0 && int64(threads) > size/minChunkSize {
+		threads = int(size / minChunkSize)
+	}
+	if threads < 1 {
+		threads = 1
+	}
+	return threads
+}
+
+// 按线程数平均切分
+func splitChunks(size int64, threads int) []*chunk {
+	chunks := make([]*chunk, 0, threads)
+	step := size / int64(threads)
+	for i := 0; i < threads; i++ {
+		start := int64(i) * step
+		end := start + step - 1
+		if i == threads-1 {
+			end = size - 1
+		}
+		chunks = append(chunks, &chunk{Start: start, End: end})
+	}
+	return chunks
+}
+
+// 依次尝试各镜像单线程下载,无法续传所以每次从头开始
+func downloadSingleFailover(ctx context.Context, client *http.Client, task DownloadTask, mirrors []*mirror) ([]byte, error) {
+	var err error
+	for i, m := range mirrors {
+		if i > 0 && task.Mirror != nil {
+			task.Mirror(m.URL)
+		}
+		var sum []byte
+		if sum, err = downloadSingle(ctx, client, task, m.URL); err == nil {
+			return sum, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+// 单线程下载,边下载边计算哈希
+func downloadSingle(ctx context.Context, client *http.Client, task DownloadTask, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载失败,网络错误码: %d", resp.StatusCode)
+	}
+
+	// 旧文件可能是缓存的硬链接,先删除避免改写缓存
+	os.Remove(task.FilePath)
+	out, err := os.Create(task.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer out.Close()
+
+	var done atomic.Int64
+	stop := reportProgress(&done, resp.ContentLength, task.Progress)
+	defer stop()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hasher, counter{&done}), resp.Body); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
+// 分段下载共享的上下文
+type rangedDownload struct {
+	client  *http.Client
+	mirrors *mirrorSet
+	out     io.WriterAt
+	state   *downloadState
+	done    *atomic.Int64
+}
+
+// 多线程分段下载,各分段通过WriteAt写入同一个文件,进度定期保存到状态文件
+func downloadRanged(ctx context.Context, client *http.Client, task DownloadTask, mirrors []*mirror, threads int) error {
+	remote := mirrors[0].remote
+	stateFile := statePath(task.FilePath)
+	state := loadState(stateFile, task, mirrors[0])
+
+	flag := os.O_RDWR | os.O_CREATE
+	if state == nil {
+		state = newState(task, mirrors[0], splitChunks(remote.Size, threads))
+		flag |= os.O_TRUNC
+		// 旧文件可能是缓存的硬链接,先删除避免改写缓存
+		os.Remove(task.FilePath)
+	} else if task.Resume != nil {
+		task.Resume(state.doneBytes(), remote.Size)
+	}
+
+	out, err := os.OpenFile(task.FilePath, flag, 0644)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer out.Close()
+
+	if err := out.Truncate(remote.Size); err != nil {
+		return fmt.Errorf("预分配文件失败: %w", err)
+	}
+
+	var done atomic.Int64
+	done.Store(state.doneBytes())
+	stop := reportProgress(&done, remote.Size, func(d, total int64) {
+		state.save(stateFile)
+		if task.Progress != nil {
+			task.Progress(d, total)
+		}
+	})
+
+	job := &rangedDownload{
+		client:  client,
+		mirrors: newMirrorSet(mirrors, task.Mirror),
+		out:     out,
+		state:   state,
+		done:    &done,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, c := range state.Chunks {
+		wg.Add(1)
+		go func(c *chunk) {
+			defer wg.Done()
+			if err := job.fetchChunk(ctx, c); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(c)
+	}
+	wg.Wait()
+	stop()
+
+	if firstErr != nil {
+		// 保留已下载部分,下次继续
+		state.save(stateFile)
+		return firstErr
+	}
+	os.Remove(stateFile)
+	return nil
+}
+
+// 下载单个分段,出错后切换镜像并从已完成位置继续重试
+func (j *rangedDownload) fetchChunk(ctx context.Context, c *chunk) error {
+	var err error
+	m := j.mirrors.get()
+	for attempt := 0; attempt < chunkRetries+j.mirrors.size(); attempt++ {
+		if err = j.fetchRange(ctx, c, m); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, errRemoteChanged) {
+			return err
+		}
+		m = j.mirrors.failover(m)
+	}
+	return err
+}
+
+// 请求分段剩余区间并写入对应偏移
+func (j *rangedDownload) fetchRange(ctx context.Context, c *chunk, m *mirror) error {
+	start := c.Start + j.state.chunkDone(c)
+	if start > c.End {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, c.End))
+	// 校验值只对探测时的同一镜像有效
+	ifRange := ifRangeValue(m.remote)
+	if ifRange != "" {
+		req.Header.Set("If-Range", ifRange)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK && ifRange != "":
+		// If-Range不匹配时服务器返回完整文件
+		return errRemoteChanged
+	case resp.StatusCode != http.StatusPartialContent:
+		return fmt.Errorf("分段下载失败,网络错误码: %d", resp.StatusCode)
+	}
+
+	w := io.NewOffsetWriter(j.out, start)
+	buffer := make([]byte, 64*1024)
+	for {
+		n, readErr := resp.Body.Read(buffer)
+		if n > 0 {
+			if _, err := w.Write(buffer[:n]); err != nil {
+				return fmt.Errorf("写入文件失败: %w", err)
+			}
+			j.state.advance(c, int64(n))
+			j.done.Add(int64(n))
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	if c.Start+j.state.chunkDone(c) <= c.End {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// If-Range只接受强ETag,否则使用Last-Modified
+func ifRangeValue(remote remoteFile) string {
+	if remote.ETag != "" && !strings.HasPrefix(remote.ETag, "W/") {
+		return remote.ETag
+	}
+	return remote.LastModified
+}
+
+// 定时回调下载进度,返回停止函数
+func reportProgress(done *atomic.Int64, total int64, fn func(done, total int64)) func() {
+	if fn == nil {
+		return func() {}
+	}
+	quit := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fn(done.Load(), total)
+			case <-quit:
+				fn(done.Load(), total)
+				return
+			}
+		}
+	}()
+	return func() {
+		close(quit)
+		<-finished
+	}
+}
+
+// 计数写入器
+type counter struct {
+	n *atomic.Int64
+}
+
+func (c counter) Write(p []byte) (int, error) {
+	c.n.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// 计算文件Sha256,ctx取消时中止
+func hashFile(ctx context.Context, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, ctxReader{ctx, f}); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
+// 比较哈希,期望值为空时跳过
+func checkSha256(sum []byte, expected string) error {
+	expected = normalizeSha256(expected)
+	if expected == "" {
+		return nil
+	}
+	if hex.EncodeToString(sum) != expected {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// 统一哈希格式,去除0x前缀并转小写
+func normalizeSha256(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.TrimPrefix(s, "0x")
+}
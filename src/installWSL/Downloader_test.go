@@ -0,0 +1,164 @@
+package installWSL
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 随机内容及其Sha256
+func testPayload(t *testing.T, size int) ([]byte, string) {
+	t.Helper()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:])
+}
+
+func TestDownloadFile(t *testing.T) {
+	data, sum := testPayload(t, 5<<20+123)
+	cases := []struct {
+		name   string
+		ranged bool
+	}{
+		{"ranged", true},
+		// 服务器忽略Range,始终返回200与完整内容
+		{"fallback200", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var rangeRequests atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !c.ranged {
+					w.Write(data)
+					return
+				}
+				if r.Header.Get("Range") != "" {
+					rangeRequests.Add(1)
+				}
+				http.ServeContent(w, r, "x.wsl", time.Time{}, bytes.NewReader(data))
+			}))
+			defer srv.Close()
+
+			path := filepath.Join(t.TempDir(), "x.wsl")
+			var last atomic.Int64
+			task := DownloadTask{
+				URLs:     []string{srv.URL},
+				FilePath: path,
+				// 清单中的校验值可能带 0x 前缀
+				Sha256:   "0x" + sum,
+				Threads:  4,
+				Progress: func(done, total int64) { last.Store(done) },
+			}
+			if err := DownloadFile(context.Background(), task); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("下载内容不一致")
+			}
+			if last.Load() != int64(len(data)) {
+				t.Fatalf("最终进度 = %d, 期望 %d", last.Load(), len(data))
+			}
+			// 探测请求之外还应有多个分段请求
+			if c.ranged && rangeRequests.Load() < 4 {
+				t.Fatalf("分段请求数 = %d", rangeRequests.Load())
+			}
+			if _, err := os.Stat(statePath(path)); !os.IsNotExist(err) {
+				t.Fatal("下载完成后仍残留状态文件")
+			}
+		})
+	}
+}
+
+func TestDownloadFileChecksumMismatch(t *testing.T) {
+	data, _ := testPayload(t, 1<<20)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "x.wsl", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	task := DownloadTask{URLs: []string{srv.URL}, FilePath: filepath.Join(t.TempDir(), "x.wsl"), Sha256: "00", Threads: 2}
+	if err := DownloadFile(context.Background(), task); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("err = %v, 期望 ErrChecksum", err)
+	}
+}
+
+// 第一次下载时每个分段只返回部分内容后断开连接,第二次应从状态文件继续
+func TestDownloadFileResume(t *testing.T) {
+	data, sum := testPayload(t, 8<<20)
+	var failing atomic.Bool
+	failing.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		rng := r.Header.Get("Range")
+		if failing.Load() && rng != "" && rng != "bytes=0-0" {
+			var start, end int64
+			fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(buf, "HTTP/1.1 206 Partial Content\r\nContent-Length: %d\r\nContent-Range: bytes %d-%d/%d\r\n\r\n", end-start+1, start, end, len(data))
+			buf.Write(data[start : start+1000])
+			buf.Flush()
+			conn.Close()
+			return
+		}
+		http.ServeContent(w, r, "x.wsl", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "x.wsl")
+	task := DownloadTask{URLs: []string{srv.URL}, FilePath: path, Sha256: sum, Threads: 4}
+	if err := DownloadFile(context.Background(), task); err == nil {
+		t.Fatal("连接中断时应返回错误")
+	}
+	if _, err := os.Stat(statePath(path)); err != nil {
+		t.Fatalf("中断后没有保存状态文件: %v", err)
+	}
+
+	failing.Store(false)
+	var resumed atomic.Int64
+	resumed.Store(-1)
+	task.Resume = func(done, total int64) { resumed.Store(done) }
+	if err := DownloadFile(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Load() <= 0 {
+		t.Fatalf("没有从已下载的 %d 字节继续", resumed.Load())
+	}
+	if _, err := os.Stat(statePath(path)); !os.IsNotExist(err) {
+		t.Fatal("下载完成后仍残留状态文件")
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	chunks := splitChunks(10, 3)
+	var next int64
+	for _, c := range chunks {
+		if c.Start != next || c.End < c.Start {
+			t.Fatalf("分段不连续: %+v", chunks)
+		}
+		next = c.End + 1
+	}
+	if next != 10 {
+		t.Fatalf("分段未覆盖整个文件: %+v", chunks)
+	}
+}
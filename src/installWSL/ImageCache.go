@@ -0,0 +1,355 @@
+package installWSL
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 缓存容量上限默认值
+	defaultCacheMaxBytes = 20 << 30
+	// 缓存过期时间默认值
+	defaultCacheMaxAge = 30 * 24 * time.Hour
+
+	cacheImageSuffix = ".image"
+	cacheMetaSuffix  = ".json"
+)
+
+// 镜像缓存条目
+type CacheEntry struct {
+	Sha256   string    `json:"sha256"`
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// 镜像缓存概况
+type CacheSummary struct {
+	Dir       string       `json:"dir"`
+	TotalSize int64        `json:"totalSize"`
+	MaxBytes  int64        `json:"maxBytes"`
+	Entries   []CacheEntry `json:"entries"`
+}
+
+// 以Sha256为键的本地镜像缓存
+type ImageCache struct {
+	Dir      string
+	MaxBytes int64
+	MaxAge   time.Duration
+
+	mu sync.Mutex
+}
+
+var DefaultCache = NewImageCache(defaultCacheDir())
+
+func NewImageCache(dir string) *ImageCache {
+	return &ImageCache{
+		Dir:      dir,
+		MaxBytes: defaultCacheMaxBytes,
+		MaxAge:   defaultCacheMaxAge,
+	}
+}
+
+// 默认缓存目录 %LocalAppData%\Easy-WSL-GUI\images
+func defaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "Easy-WSL-GUI", "images")
+}
+
+func (c *ImageCache) imagePath(sha string) string {
+	return filepath.Join(c.Dir, sha+cacheImageSuffix)
+}
+
+func (c *ImageCache) metaPath(sha string) string {
+	return filepath.Join(c.Dir, sha+cacheMetaSuffix)
+}
+
+// 从缓存取出镜像到dst,缓存不存在或校验失败时返回错误
+func (c *ImageCache) Restore(ctx context.Context, sha, dst string) error {
+	sha = normalizeSha256(sha)
+	if !validSha256(sha) {
+		return errors.New("缓存键不是有效的Sha256")
+	}
+
+	// 校验整个镜像耗时较长,期间不持有锁,以免阻塞界面轮询的 Summary/List
+	src := c.imagePath(sha)
+	c.mu.Lock()
+	before, err := os.Stat(src)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	sum, err := hashFile(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 校验期间被淘汰或替换时不能使用本次校验结果
+	after, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(before, after) || !after.ModTime().Equal(before.ModTime()) || after.Size() != before.Size() {
+		return errors.New("缓存镜像在校验期间被修改")
+	}
+	if hex.EncodeToString(sum) != sha {
+		// 缓存文件损坏,直接丢弃
+		c.removeLocked(sha)
+		return ErrChecksum
+	}
+
+	if err := linkOrCopy(src, dst); err != nil {
+		return err
+	}
+	return c.touchLocked(sha)
+}
+
+// 将已校验的镜像存入缓存
+func (c *ImageCache) Store(sha, name, src string) error {
+	sha = normalizeSha256(sha)
+	if !validSha256(sha) {
+		return errors.New("缓存键不是有效的Sha256")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+
+	dst := c.imagePath(sha)
+	if _, err := os.Stat(dst); err != nil {
+		if err := linkOrCopy(src, dst); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	return c.writeMetaLocked(CacheEntry{
+		Sha256:   sha,
+		Name:     name,
+		Size:     info.Size(),
+		LastUsed: time.Now(),
+	})
+}
+
+// 查询缓存条目,不校验内容也不更新使用时间
+func (c *ImageCache) Lookup(sha string) (CacheEntry, bool) {
+	sha = normalizeSha256(sha)
+	if !validSha256(sha) {
+		return CacheEntry{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, err := c.readMetaLocked(sha)
+	return entry, err == nil
+}
+
+// 列出缓存条目,最近使用的在前
+func (c *ImageCache) List() ([]CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.listLocked()
+}
+
+// 缓存概况
+func (c *ImageCache) Summary() (CacheSummary, error) {
+	entries, err := c.List()
+	if err != nil {
+		return CacheSummary{}, err
+	}
+	summary := CacheSummary{
+		Dir:      c.Dir,
+		MaxBytes: c.MaxBytes,
+		Entries:  entries,
+	}
+	for _, e := range entries {
+		summary.TotalSize += e.Size
+	}
+	return summary, nil
+}
+
+// 淘汰过期条目,再按最久未使用淘汰直到不超过容量上限
+func (c *ImageCache) Evict() ([]CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.listLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+
+	var evicted []CacheEntry
+	// 从最久未使用的开始
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		expired := c.MaxAge > 0 && time.Since(e.LastUsed) > c.MaxAge
+		oversize := c.MaxBytes > 0 && total > c.MaxBytes
+		if !expired && !oversize {
+			continue
+		}
+		if err := c.removeLocked(e.Sha256); err != nil {
+			return evicted, err
+		}
+		total -= e.Size
+		evicted = append(evicted, e)
+	}
+	return evicted, nil
+}
+
+// 清空缓存
+func (c *ImageCache) Purge() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.listLocked()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := c.removeLocked(e.Sha256); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ImageCache) listLocked() ([]CacheEntry, error) {
+	files, err := os.ReadDir(c.Dir)
+	if os.IsNotExist(err) {
+		return []CacheEntry{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries := []CacheEntry{}
+	for _, f := range files {
+		sha, ok := strings.CutSuffix(f.Name(), cacheMetaSuffix)
+		if !ok || !validSha256(sha) {
+			continue
+		}
+		entry, err := c.readMetaLocked(sha)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+func (c *ImageCache) readMetaLocked(sha string) (CacheEntry, error) {
+	var entry CacheEntry
+	data, err := os.ReadFile(c.metaPath(sha))
+	if err != nil {
+		return entry, err
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, err
+	}
+	// 元数据还在但镜像已被删除
+	info, err := os.Stat(c.imagePath(sha))
+	if err != nil {
+		return entry, err
+	}
+	entry.Sha256 = sha
+	entry.Size = info.Size()
+	return entry, nil
+}
+
+func (c *ImageCache) writeMetaLocked(entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.metaPath(entry.Sha256), data, 0644)
+}
+
+// 更新最近使用时间
+func (c *ImageCache) touchLocked(sha string) error {
+	entry, err := c.readMetaLocked(sha)
+	if err != nil {
+		return err
+	}
+	entry.LastUsed = time.Now()
+	return c.writeMetaLocked(entry)
+}
+
+func (c *ImageCache) removeLocked(sha string) error {
+	if err := os.Remove(c.imagePath(sha)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(c.metaPath(sha)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func validSha256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// 同一分区下优先硬链接,否则复制
+func linkOrCopy(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	os.Remove(dst)
+	if os.Link(src, dst) == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,78 @@
+package installWSL
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewImageCache(filepath.Join(dir, "cache"))
+	src := filepath.Join(dir, "a.wsl")
+	if err := os.WriteFile(src, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("hello"))
+	sha := "0x" + hex.EncodeToString(sum[:])
+
+	if err := cache.Store(sha, "A", src); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := cache.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Size != 5 || entries[0].Name != "A" {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	dst := filepath.Join(dir, "x", "b.wsl")
+	if err := cache.Restore(context.Background(), sha, dst); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(dst); string(got) != "hello" {
+		t.Fatalf("恢复的内容 = %q", got)
+	}
+
+	// 超出容量时按最近使用时间淘汰
+	cache.MaxBytes = 1
+	evicted, err := cache.Evict()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 1 {
+		t.Fatalf("evicted = %+v", evicted)
+	}
+	if cache.Restore(context.Background(), sha, filepath.Join(dir, "c")) == nil {
+		t.Fatal("淘汰后不应命中缓存")
+	}
+}
+
+// 缓存文件损坏时丢弃条目
+func TestImageCacheCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewImageCache(filepath.Join(dir, "cache"))
+	src := filepath.Join(dir, "a.wsl")
+	if err := os.WriteFile(src, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("hello"))
+	sha := hex.EncodeToString(sum[:])
+	if err := cache.Store(sha, "A", src); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cache.imagePath(sha), []byte("world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Restore(context.Background(), sha, filepath.Join(dir, "b.wsl")); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("err = %v, 期望 ErrChecksum", err)
+	}
+	if _, ok := cache.Lookup(sha); ok {
+		t.Fatal("损坏的条目没有被丢弃")
+	}
+}
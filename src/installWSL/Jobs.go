@@ -0,0 +1,182 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrJobNotFound = errors.New("任务不存在或已结束")
+
+// 任务状态
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobDone      JobStatus = "done"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllJobStatuses = []struct {
+	Value  JobStatus
+	TSName string
+}{
+	{JobQueued, "Queued"},
+	{JobRunning, "Running"},
+	{JobDone, "Done"},
+	{JobFailed, "Failed"},
+	{JobCancelled, "Cancelled"},
+}
+
+// 后台执行的安装任务
+type Job struct {
+	ID      string
+	Kind    string
+	Distro  string
+	Created time.Time
+
+	cancel   context.CancelFunc
+	imported atomic.Bool
+	queue    *Queue
+
+	mu       sync.Mutex
+	status   JobStatus
+	phase    Phase
+	err      string
+	started  time.Time
+	finished time.Time
+}
+
+// 任务概况,供前端展示队列
+type JobInfo struct {
+	ID     string    `json:"id"`
+	Kind   string    `json:"kind"`
+	Distro string    `json:"distro"`
+	Status JobStatus `json:"status"`
+	Phase  Phase     `json:"phase"`
+	Error  string    `json:"error"`
+	// 排队中的任务从0开始的位置,其余为-1
+	Position int       `json:"position"`
+	Created  time.Time `json:"created"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+type jobKey struct{}
+
+var jobSeq atomic.Int64
+
+func newJob(parent context.Context, kind, distro string) (*Job, context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	job := &Job{
+		ID:      fmt.Sprintf("%s-%d-%d", kind, time.Now().Unix(), jobSeq.Add(1)),
+		Kind:    kind,
+		Distro:  distro,
+		Created: time.Now(),
+		cancel:  cancel,
+		status:  JobQueued,
+	}
+	return job, context.WithValue(ctx, jobKey{}, job)
+}
+
+// 从ctx取出所属任务,不在任务中执行时返回nil
+func JobFromContext(ctx context.Context) *Job {
+	job, _ := ctx.Value(jobKey{}).(*Job)
+	return job
+}
+
+// 任务当前状态
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// 任务是否已开始执行,排队中被取消的任务无需清理
+func (j *Job) Started() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return !j.started.IsZero()
+}
+
+func (j *Job) info(position int) JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return JobInfo{
+		ID:       j.ID,
+		Kind:     j.Kind,
+		Distro:   j.Distro,
+		Status:   j.status,
+		Phase:    j.phase,
+		Error:    j.err,
+		Position: position,
+		Created:  j.Created,
+		Started:  j.started,
+		Finished: j.finished,
+	}
+}
+
+func (j *Job) setRunning() {
+	j.mu.Lock()
+	j.status = JobRunning
+	j.started = time.Now()
+	j.mu.Unlock()
+}
+
+// 根据执行结果记录最终状态
+func (j *Job) setFinished(ctxErr, err error) {
+	j.mu.Lock()
+	switch {
+	case err == nil:
+		j.status = JobDone
+	case ctxErr != nil:
+		j.status = JobCancelled
+	default:
+		j.status = JobFailed
+		j.err = err.Error()
+	}
+	j.finished = time.Now()
+	j.mu.Unlock()
+	j.cancel()
+	forgetProgress(j.ID)
+}
+
+// 记录阶段变化,变化时返回true
+func (j *Job) setPhase(p Phase) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.phase == p {
+		return false
+	}
+	j.phase = p
+	return true
+}
+
+// 本任务是否已开始导入发行版,取消时据此决定是否注销
+func (j *Job) Imported() bool {
+	return j.imported.Load()
+}
+
+func markImported(ctx context.Context) {
+	if job := JobFromContext(ctx); job != nil {
+		job.imported.Store(true)
+	}
+}
+
+// 可被取消的等待
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+package installWSL
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+// 本地镜像压缩格式
+const (
+	FormatTar  = "tar"
+	FormatGzip = "gzip"
+	FormatXz   = "xz"
+)
+
+var (
+	ErrUnknownFormat = errors.New("无法识别的镜像格式,仅支持 .wsl/.tar/.tar.gz/.tar.xz")
+	ErrNotRootfs     = errors.New("压缩包不是有效的Linux根文件系统")
+)
+
+var distroNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// 发行版名称是否可用于 wsl --import
+func ValidDistroName(name string) bool {
+	return distroNamePattern.MatchString(name)
+}
+
+// 根据文件头判断压缩格式
+func DetectArchiveFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return FormatGzip, nil
+	case bytes.HasPrefix(head, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return FormatXz, nil
+	case len(head) >= 262 && bytes.HasPrefix(head[257:], []byte("ustar")):
+		return FormatTar, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// 打开解压后的tar数据流
+func openTarStream(path, format string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = bufio.NewReaderSize(f, 1<<20)
+	switch format {
+	case FormatGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r = gz
+	case FormatXz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r = xr
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
+
+// 检查压缩包内是否存在 /etc/os-release 与 /bin/sh,兼容usr合并后的布局
+func ValidateRootfs(ctx context.Context, path string) (string, error) {
+	format, err := DetectArchiveFormat(path)
+	if err != nil {
+		return "", err
+	}
+
+	stream, err := openTarStream(path, format)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNotRootfs, err)
+	}
+	defer stream.Close()
+
+	var hasOSRelease, hasShell bool
+	tr := tar.NewReader(ctxReader{ctx, stream})
+	for !hasOSRelease || !hasShell {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", fmt.Errorf("%w: %v", ErrNotRootfs, err)
+		}
+
+		switch strings.TrimLeft(strings.TrimPrefix(hdr.Name, "./"), "/") {
+		case "etc/os-release", "usr/lib/os-release":
+			hasOSRelease = true
+		case "bin/sh", "usr/bin/sh":
+			hasShell = true
+		}
+	}
+
+	if !hasOSRelease {
+		return "", fmt.Errorf("%w: 缺少 /etc/os-release", ErrNotRootfs)
+	}
+	if !hasShell {
+		return "", fmt.Errorf("%w: 缺少 /bin/sh", ErrNotRootfs)
+	}
+	return format, nil
+}
+
+// 校验用户提供的Sha256,为空时跳过
+func VerifyChecksum(ctx context.Context, path, expected string) error {
+	if strings.TrimSpace(expected) == "" {
+		return nil
+	}
+	if !validSha256(normalizeSha256(expected)) {
+		return errors.New("提供的Sha256格式不正确")
+	}
+	sum, err := hashFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	return checkSha256(sum, expected)
+}
+
+// 每次读取前检查ctx,使解压、校验等长时间的读取可被取消
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// 准备可供 wsl --import 直接使用的文件,xz格式先解压为tar放在workDir下;
+// 出错或ctx取消时删除已写入的临时文件
+func PrepareImportFile(ctx context.Context, path, format, workDir string) (string, func(), error) {
+	if format != FormatXz {
+		return path, func() {}, nil
+	}
+
+	stream, err := openTarStream(path, format)
+	if err != nil {
+		return "", nil, err
+	}
+	defer stream.Close()
+
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return "", nil, err
+	}
+	out, err := os.CreateTemp(workDir, "rootfs-*.tar")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(out.Name()) }
+
+	if _, err := io.Copy(out, ctxReader{ctx, stream}); err != nil {
+		out.Close()
+		cleanup()
+		if ctx.Err() != nil {
+			return "", nil, ctx.Err()
+		}
+		return "", nil, fmt.Errorf("解压xz镜像失败: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	// 解压刚结束时被取消
+	if ctx.Err() != nil {
+		cleanup()
+		return "", nil, ctx.Err()
+	}
+	return filepath.Clean(out.Name()), cleanup, nil
+}
+
+// 校验本地镜像并返回导入用的文件路径,调用方安装完成后需执行cleanup
+func WSL2_LocalImage(ctx context.Context, Info WSLinfo, checksum string) (string, func(), error) {
+	format, err := ValidateRootfs(ctx, Info.Local_File)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := VerifyChecksum(ctx, Info.Local_File, checksum); err != nil {
+		return "", nil, err
+	}
+	return PrepareImportFile(ctx, Info.Local_File, format, Info.Install_Path.Path)
+}
@@ -0,0 +1,144 @@
+package installWSL
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+// 在dir下生成指定格式的根文件系统压缩包
+func writeRootfs(t *testing.T, dir, name, format string, files ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		body := []byte("#" + f + "\n")
+		if err := tw.WriteHeader(&tar.Header{Name: f, Mode: 0644, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(body)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	var w io.WriteCloser
+	switch format {
+	case FormatGzip:
+		w = gzip.NewWriter(&out)
+	case FormatXz:
+		xw, err := xz.NewWriter(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w = xw
+	default:
+		out = buf
+	}
+	if w != nil {
+		w.Write(buf.Bytes())
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLocalImage(t *testing.T) {
+	src := t.TempDir()
+	cases := []struct {
+		name, format string
+	}{
+		{"rootfs.tar", FormatTar},
+		{"rootfs.tar.gz", FormatGzip},
+		{"rootfs.tar.xz", FormatXz},
+	}
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			path := writeRootfs(t, src, c.name, c.format, "./etc/os-release", "./usr/bin/sh")
+			format, err := ValidateRootfs(context.Background(), path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != c.format {
+				t.Fatalf("format = %s, 期望 %s", format, c.format)
+			}
+
+			work := t.TempDir()
+			out, cleanup, err := PrepareImportFile(context.Background(), path, format, work)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 仅xz需要解压为tar
+			if (c.format == FormatXz) == (out == path) {
+				t.Fatalf("导入文件 = %s", out)
+			}
+			if got, err := ValidateRootfs(context.Background(), out); err != nil || got == FormatXz {
+				t.Fatalf("导入文件无效: %s %v", got, err)
+			}
+			cleanup()
+			if c.format == FormatXz {
+				if _, err := os.Stat(out); !os.IsNotExist(err) {
+					t.Fatal("cleanup 后仍残留临时文件")
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRootfsMissingShell(t *testing.T) {
+	path := writeRootfs(t, t.TempDir(), "bad.tar", FormatTar, "etc/os-release", "etc/hostname")
+	if _, err := ValidateRootfs(context.Background(), path); !errors.Is(err, ErrNotRootfs) {
+		t.Fatalf("err = %v, 期望 ErrNotRootfs", err)
+	}
+}
+
+func TestPrepareImportFileCancelled(t *testing.T) {
+	path := writeRootfs(t, t.TempDir(), "rootfs.tar.xz", FormatXz, "etc/os-release", "bin/sh")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	work := t.TempDir()
+	if _, _, err := PrepareImportFile(ctx, path, FormatXz, work); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, 期望 context.Canceled", err)
+	}
+	entries, err := os.ReadDir(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("取消后仍残留 %d 个文件", len(entries))
+	}
+}
+
+func TestLocalImageCancelled(t *testing.T) {
+	path := writeRootfs(t, t.TempDir(), "rootfs.tar.gz", FormatGzip, "etc/os-release", "bin/sh")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ValidateRootfs(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ValidateRootfs err = %v, 期望 context.Canceled", err)
+	}
+	sum, err := hashFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChecksum(ctx, path, hex.EncodeToString(sum)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("VerifyChecksum err = %v, 期望 context.Canceled", err)
+	}
+}
@@ -0,0 +1,149 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 单个镜像探测超时
+const probeTimeout = 10 * time.Second
+
+// 镜像源及其探测结果
+type mirror struct {
+	URL     string
+	Latency time.Duration
+	remote  remoteFile
+}
+
+// 并发探测所有镜像,首选镜像排在最前,其余按延迟排序,剔除不可用或文件大小不一致的镜像
+func rankMirrors(ctx context.Context, client *http.Client, urls []string, prefer string) ([]*mirror, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("没有可用的下载地址")
+	}
+
+	results := make([]*mirror, len(urls))
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+
+			start := time.Now()
+			remote, err := probeRange(pctx, client, u)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", mirrorHost(u), err)
+				return
+			}
+			results[i] = &mirror{URL: u, Latency: time.Since(start), remote: remote}
+		}(i, u)
+	}
+	wg.Wait()
+
+	var alive []*mirror
+	for _, m := range results {
+		if m != nil {
+			alive = append(alive, m)
+		}
+	}
+	if len(alive) == 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("所有镜像均不可用: %w", errors.Join(errs...))
+	}
+
+	sort.SliceStable(alive, func(i, j int) bool {
+		pi, pj := isPreferred(alive[i].URL, prefer), isPreferred(alive[j].URL, prefer)
+		if pi != pj {
+			return pi
+		}
+		return alive[i].Latency < alive[j].Latency
+	})
+
+	// 以排在第一的镜像为准,大小不同说明文件不是同一个
+	best := alive[0]
+	if best.remote.Size <= 0 {
+		return alive, nil
+	}
+	same := alive[:0]
+	for _, m := range alive {
+		if m.remote.Size == best.remote.Size {
+			same = append(same, m)
+		}
+	}
+	return same, nil
+}
+
+// 镜像主机名包含首选关键字,如 tuna、ustc
+func isPreferred(u, prefer string) bool {
+	prefer = strings.ToLower(strings.TrimSpace(prefer))
+	return prefer != "" && strings.Contains(strings.ToLower(mirrorHost(u)), prefer)
+}
+
+func mirrorHost(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return parsed.Host
+}
+
+// 下载过程中共享的镜像列表,出错时切换到下一个
+type mirrorSet struct {
+	mu       sync.Mutex
+	list     []*mirror
+	current  int
+	onSwitch func(url string)
+}
+
+func newMirrorSet(list []*mirror, onSwitch func(url string)) *mirrorSet {
+	return &mirrorSet{list: list, onSwitch: onSwitch}
+}
+
+// 当前使用的镜像
+func (s *mirrorSet) get() *mirror {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list[s.current]
+}
+
+// 标记镜像失败,若它仍是当前镜像则切换到下一个,返回切换后的镜像
+func (s *mirrorSet) failover(failed *mirror) *mirror {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.list) > 1 && s.list[s.current] == failed {
+		s.current = (s.current + 1) % len(s.list)
+		if s.onSwitch != nil {
+			s.onSwitch(s.list[s.current].URL)
+		}
+	}
+	return s.list[s.current]
+}
+
+func (s *mirrorSet) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.list)
+}
+
+// 只保留支持Range的镜像
+func rangedMirrors(list []*mirror) []*mirror {
+	var ranged []*mirror
+	for _, m := range list {
+		if m.remote.Ranged {
+			ranged = append(ranged, m)
+		}
+	}
+	return ranged
+}
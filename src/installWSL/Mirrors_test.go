@@ -0,0 +1,109 @@
+package installWSL
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 支持Range的镜像,delay模拟网络延迟
+func serveMirror(t *testing.T, data []byte, delay time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		http.ServeContent(w, r, "x.wsl", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRankMirrors(t *testing.T) {
+	data, _ := testPayload(t, 4096)
+	fast := serveMirror(t, data, 0)
+	slow := serveMirror(t, data, 50*time.Millisecond)
+	preferred := serveMirror(t, data, 100*time.Millisecond)
+	// 大小不同的镜像不是同一个文件
+	other := serveMirror(t, data[:100], 0)
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+
+	urls := []string{dead.URL, slow.URL, other.URL, fast.URL, preferred.URL}
+	mirrors, err := rankMirrors(context.Background(), http.DefaultClient, urls, mirrorHost(preferred.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range mirrors {
+		got = append(got, m.URL)
+	}
+	want := []string{preferred.URL, fast.URL, slow.URL}
+	if len(got) != len(want) {
+		t.Fatalf("镜像 = %v, 期望 %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("镜像 = %v, 期望 %v", got, want)
+		}
+	}
+
+	if _, err := rankMirrors(context.Background(), http.DefaultClient, []string{dead.URL}, ""); err == nil {
+		t.Fatal("所有镜像不可用时应返回错误")
+	}
+}
+
+// 首选镜像探测正常但分段下载失败,应切换到下一个镜像完成下载
+func TestDownloadFileMirrorFailover(t *testing.T) {
+	data, sum := testPayload(t, 4<<20)
+	good := serveMirror(t, data, 20*time.Millisecond)
+	var badHits atomic.Int64
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "bytes=0-0" {
+			http.ServeContent(w, r, "x.wsl", time.Time{}, bytes.NewReader(data))
+			return
+		}
+		badHits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	var mu sync.Mutex
+	var switched []string
+	path := filepath.Join(t.TempDir(), "x.wsl")
+	task := DownloadTask{
+		URLs:            []string{good.URL, bad.URL},
+		PreferredMirror: mirrorHost(bad.URL),
+		FilePath:        path,
+		Sha256:          sum,
+		Threads:         4,
+		Mirror: func(u string) {
+			mu.Lock()
+			defer mu.Unlock()
+			switched = append(switched, u)
+		},
+	}
+	if err := DownloadFile(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("下载内容不一致")
+	}
+	if badHits.Load() == 0 {
+		t.Fatal("没有先使用首选镜像")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(switched) < 2 || switched[0] != bad.URL || switched[len(switched)-1] != good.URL {
+		t.Fatalf("镜像切换 = %v", switched)
+	}
+}
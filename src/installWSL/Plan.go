@@ -0,0 +1,253 @@
+package installWSL
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	audit "Golang-WSL-GUI/src/Audit"
+	catalog "Golang-WSL-GUI/src/Catalog"
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 预演步骤类型
+type StepKind string
+
+const (
+	StepCommand      StepKind = "command"
+	StepFileWrite    StepKind = "file-write"
+	StepFileDelete   StepKind = "file-delete"
+	StepRegistryRead StepKind = "registry-read"
+	StepDownload     StepKind = "download"
+	StepWait         StepKind = "wait"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllStepKinds = []struct {
+	Value  StepKind
+	TSName string
+}{
+	{StepCommand, "Command"},
+	{StepFileWrite, "FileWrite"},
+	{StepFileDelete, "FileDelete"},
+	{StepRegistryRead, "RegistryRead"},
+	{StepDownload, "Download"},
+	{StepWait, "Wait"},
+}
+
+// 预演中的一步操作
+type PlanStep struct {
+	Kind        StepKind `json:"kind"`
+	Description string   `json:"description"`
+	// 将执行的命令行,口令已脱敏
+	Command []string `json:"command"`
+	// 读写的文件或注册表路径
+	Path string `json:"path"`
+	// 涉及的字节数,未知时为0
+	Size int64 `json:"size"`
+	// 仅在满足条件时执行,为空表示总会执行
+	Condition string `json:"condition"`
+}
+
+// 一次操作的预演结果,按执行顺序列出,不会真正执行
+type Plan struct {
+	Operation string     `json:"operation"`
+	Distro    string     `json:"distro"`
+	Steps     []PlanStep `json:"steps"`
+	// 无法预先确定的情况
+	Notes []string `json:"notes"`
+
+	// 下一条记录命令的说明与条件
+	desc      string
+	condition string
+}
+
+func NewPlan(operation, distro string) *Plan {
+	return &Plan{Operation: operation, Distro: distro, Steps: []PlanStep{}, Notes: []string{}}
+}
+
+// 追加一步
+func (p *Plan) Add(step PlanStep) {
+	p.Steps = append(p.Steps, step)
+}
+
+// 追加说明
+func (p *Plan) Note(format string, args ...any) {
+	p.Notes = append(p.Notes, fmt.Sprintf(format, args...))
+}
+
+// 实现 wslcli.Runner: 只记录命令,返回空的成功结果
+func (p *Plan) Run(ctx context.Context, cmd wslcli.Command) (wslcli.Result, error) {
+	step := PlanStep{
+		Kind:        StepCommand,
+		Description: p.desc,
+		Command:     append([]string{"wsl.exe"}, audit.Redact(cmd.Args)...),
+		Condition:   p.condition,
+	}
+	if cmd.Stdin != nil {
+		step.Description += " (通过标准输入传递内容)"
+	}
+	p.Add(step)
+	return wslcli.Result{}, nil
+}
+
+// 通过记录用的客户端调用 wslcli 操作,命令行与真实执行时完全一致
+func (p *Plan) Wsl(desc string, fn func(ctx context.Context, cli wslcli.Client)) {
+	p.WslIf("", desc, fn)
+}
+
+// 同 Wsl,附带执行条件
+func (p *Plan) WslIf(condition, desc string, fn func(ctx context.Context, cli wslcli.Client)) {
+	p.desc, p.condition = desc, condition
+	fn(context.Background(), wslcli.New(p))
+	p.desc, p.condition = "", ""
+}
+
+// 预演在线安装: 检查、下载或取缓存、导入、配置用户
+func PlanInstall(Info WSLinfo) (*Plan, error) {
+	p := NewPlan("install", Info.Linux_Version)
+	planCheck(p, Info)
+
+	image, ok := catalog.Current().Find(Info.Linux_Version)
+	if !ok || len(image.URLs) == 0 {
+		return nil, fmt.Errorf("发行版清单中没有 %s", Info.Linux_Version)
+	}
+	fullpath := FilePath_string(Info)
+	p.Add(PlanStep{Kind: StepFileWrite, Description: "创建安装目录", Path: filepath.Dir(fullpath)})
+
+	entry, cached := DefaultCache.Lookup(image.Sha256)
+	if cached {
+		p.Add(PlanStep{
+			Kind:        StepFileWrite,
+			Description: "从本地缓存复制镜像",
+			Path:        fullpath,
+			Size:        entry.Size,
+			Condition:   "缓存文件校验通过",
+		})
+		p.Note("缓存文件 %s 校验失败时将改为下载", DefaultCache.imagePath(image.Sha256))
+	}
+	download := PlanStep{
+		Kind:        StepDownload,
+		Description: fmt.Sprintf("从 %s 下载镜像并校验Sha256", mirrorHost(image.URLs[0])),
+		Command:     image.URLs,
+		Path:        fullpath,
+		Size:        image.Size,
+	}
+	if cached {
+		download.Condition = "缓存未命中"
+	}
+	if state := peekState(fullpath); state != nil {
+		download.Description += fmt.Sprintf(",从已下载的 %d/%d 字节处续传", state.doneBytes(), state.Size)
+	}
+	p.Add(download)
+	p.Add(PlanStep{
+		Kind:        StepFileWrite,
+		Description: "将镜像存入本地缓存",
+		Path:        DefaultCache.imagePath(image.Sha256),
+		Size:        image.Size,
+		Condition:   download.Condition,
+	})
+
+	// 虚拟磁盘大小取决于解压后的内容,无法预知
+	planImport(p, Info, 0)
+	planSettingUser(p, Info)
+	return p, nil
+}
+
+// 检查是否已安装,已安装且配置了默认用户时中止
+func planCheck(p *Plan, Info WSLinfo) {
+	p.Wsl("检查发行版是否已安装", func(ctx context.Context, cli wslcli.Client) {
+		cli.List(ctx)
+	})
+	p.WslIf("发行版已存在", "读取 /etc/wsl.conf 判断是否已配置默认用户", func(ctx context.Context, cli wslcli.Client) {
+		wslcli.ReadWslConf(ctx, cli, Info.Linux_Version)
+	})
+	p.Note("若 %s 已安装并配置默认用户,安装将在检查步骤中止;未配置时跳过下载与导入,只配置用户", Info.Linux_Version)
+}
+
+func planImport(p *Plan, Info WSLinfo, size int64) {
+	p.Wsl("导入发行版", func(ctx context.Context, cli wslcli.Client) {
+		cli.Import(ctx, Info.Linux_Version, Info.Install_Path.Path, FilePath_string(Info), wslcli.ImportOptions{Version: 2})
+	})
+	p.Add(PlanStep{
+		Kind:        StepFileWrite,
+		Description: "wsl.exe 创建虚拟磁盘",
+		Path:        filepath.Join(Info.Install_Path.Path, "ext4.vhdx"),
+		Size:        size,
+	})
+}
+
+func planSettingUser(p *Plan, Info WSLinfo) {
+	p.Wsl("读取 /etc/adduser.conf 中的 NAME_REGEX 校验用户名", func(ctx context.Context, cli wslcli.Client) {
+		nameRegex(ctx, cli, Info.Linux_Version)
+	})
+	p.Wsl("读取 os-release、/etc/shells、/etc/group 并探测 useradd 与 sudo", func(ctx context.Context, cli wslcli.Client) {
+		probeProvision(ctx, cli, Info.Linux_Version)
+	})
+	env := plannedEnv(Info.Linux_Version)
+	p.Note("用户配置策略为 %s,实际的管理员组与登录shell以探测结果为准", env.Strategy.Name)
+	for _, step := range provisionSteps(Info, env) {
+		p.WslIf(step.condition, step.desc, func(ctx context.Context, cli wslcli.Client) {
+			step.run(ctx, cli, Info.Linux_Version)
+		})
+	}
+	p.Wsl("结束发行版使配置生效", func(ctx context.Context, cli wslcli.Client) {
+		cli.Terminate(ctx, Info.Linux_Version)
+	})
+}
+
+// 预演卸载,disk为注册表中读到的虚拟磁盘,未找到时为nil
+func PlanUninstall(p *Plan, Info WSLinfo, disk *PlanDisk) {
+	p.Wsl("注销发行版", func(ctx context.Context, cli wslcli.Client) {
+		cli.Unregister(ctx, Info.Linux_Version)
+	})
+	if disk != nil {
+		p.Add(PlanStep{Kind: StepFileDelete, Description: "wsl.exe 删除虚拟磁盘", Path: disk.Path, Size: disk.Size})
+	}
+}
+
+// 预演迁移: 导出、注销、导入到新位置、还原用户
+func PlanMigration(p *Plan, Info WSLinfo, disk *PlanDisk) {
+	var size int64
+	if disk != nil {
+		size = disk.Size
+	}
+	tarFile := FilePath_string(Info)
+	p.Wsl("导出发行版", func(ctx context.Context, cli wslcli.Client) {
+		cli.Export(ctx, Info.Linux_Version, tarFile, wslcli.ExportOptions{})
+	})
+	p.Add(PlanStep{Kind: StepFileWrite, Description: "导出的tar文件,大小约等于虚拟磁盘已用空间", Path: tarFile, Size: size})
+	PlanUninstall(p, Info, disk)
+	planImport(p, Info, size)
+	p.Wsl("启动发行版", func(ctx context.Context, cli wslcli.Client) {
+		cli.Launch(ctx, Info.Linux_Version)
+	})
+	p.Add(PlanStep{Kind: StepWait, Description: fmt.Sprintf("等待 %s 让发行版完成启动", migrationStartDelay)})
+	step := restoreUserStep(Info)
+	p.Wsl(step.desc, func(ctx context.Context, cli wslcli.Client) {
+		step.run(ctx, cli, Info.Linux_Version)
+	})
+	p.Note("导出、注销或导入失败时会删除 %s", tarFile)
+	p.Note("迁移完成后 %s 不会被删除", tarFile)
+}
+
+// 预演中使用的虚拟磁盘信息
+type PlanDisk struct {
+	Path string
+	Size int64
+}
+
+// 读取续传状态文件,不校验远端
+func peekState(filePath string) *downloadState {
+	data, err := os.ReadFile(statePath(filePath))
+	if err != nil {
+		return nil
+	}
+	var state downloadState
+	if json.Unmarshal(data, &state) != nil {
+		return nil
+	}
+	return &state
+}
@@ -0,0 +1,206 @@
+package installWSL
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 安装阶段
+type Phase string
+
+const (
+	PhaseChecking        Phase = "checking"
+	PhaseDownloading     Phase = "downloading"
+	PhaseVerifying       Phase = "verifying"
+	PhaseImporting       Phase = "importing"
+	PhaseConfiguringUser Phase = "configuring-user"
+	PhaseDone            Phase = "done"
+	PhaseFailed          Phase = "failed"
+	PhaseCancelled       Phase = "cancelled"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllPhases = []struct {
+	Value  Phase
+	TSName string
+}{
+	{PhaseChecking, "Checking"},
+	{PhaseDownloading, "Downloading"},
+	{PhaseVerifying, "Verifying"},
+	{PhaseImporting, "Importing"},
+	{PhaseConfiguringUser, "ConfiguringUser"},
+	{PhaseDone, "Done"},
+	{PhaseFailed, "Failed"},
+	{PhaseCancelled, "Cancelled"},
+}
+
+// 安装进度事件,通过 install:progress 推送,文案由前端根据MessageKey与Params本地化
+type Progress struct {
+	JobID      string `json:"jobId"`
+	Distro     string `json:"distro"`
+	Phase      Phase  `json:"phase"`
+	BytesDone  int64  `json:"bytesDone"`
+	BytesTotal int64  `json:"bytesTotal"`
+	// 下载速度,字节/秒
+	Speed float64 `json:"speed"`
+	// 预计剩余秒数,未知时为-1
+	ETA        float64           `json:"eta"`
+	MessageKey string            `json:"messageKey"`
+	Params     map[string]string `json:"params"`
+	// 失败或警告的详细原因,Phase为failed时即任务的错误信息
+	Error string `json:"error"`
+}
+
+// 进度文案键
+const (
+	MsgCheckStart      = "check.start"
+	MsgCheckExisting   = "check.existing"
+	MsgCacheHit        = "download.cacheHit"
+	MsgDownloadMirror  = "download.mirror"
+	MsgDownloadResume  = "download.resume"
+	MsgDownloadRunning = "download.progress"
+	MsgDownloadDone    = "download.done"
+	MsgVerifyLocal     = "verify.local"
+	MsgVerifyLocalDone = "verify.localDone"
+	MsgVerifySha256    = "verify.sha256"
+	MsgImportWaiting   = "import.waiting"
+	MsgImportStart     = "import.start"
+	MsgImportDone      = "import.done"
+	MsgConfigureUser   = "user.configure"
+	MsgUserDetect      = "user.detect"
+	MsgUserDetected    = "user.detected"
+	MsgUserInstallSudo = "user.installSudo"
+	MsgUserCreate      = "user.create"
+	MsgUserPassword    = "user.password"
+	MsgUserGroup       = "user.group"
+	MsgUserSudoers     = "user.sudoers"
+	MsgUserDefault     = "user.default"
+	MsgInstallDone     = "install.done"
+	MsgInstallFailed   = "install.failed"
+	MsgInstallCancel   = "install.cancelled"
+	MsgWarning         = "warning"
+)
+
+// 推送前端事件,测试时可替换以脱离wails运行时
+var emitEvent = runtime.EventsEmit
+
+var (
+	progressMu sync.Mutex
+	// 各任务最后一次进度,供前端重新打开界面时恢复
+	lastProgress = map[string]Progress{}
+)
+
+// 推送进度事件,JobID与Distro从ctx中的任务补全
+func EmitProgress(ctx context.Context, p Progress) {
+	job := JobFromContext(ctx)
+	if job != nil {
+		if p.JobID == "" {
+			p.JobID = job.ID
+		}
+		if p.Distro == "" {
+			p.Distro = job.Distro
+		}
+	}
+	if p.Params == nil {
+		p.Params = map[string]string{}
+	}
+	if _, ok := p.Params["distro"]; !ok && p.Distro != "" {
+		p.Params["distro"] = p.Distro
+	}
+	if p.ETA == 0 && p.Speed == 0 {
+		p.ETA = -1
+	}
+
+	if p.JobID != "" {
+		progressMu.Lock()
+		lastProgress[p.JobID] = p
+		progressMu.Unlock()
+	}
+
+	emitEvent(ctx, "install:progress", p)
+	if job != nil && job.setPhase(p.Phase) && job.queue != nil {
+		job.queue.changed()
+	}
+}
+
+// 推送任务失败,错误随JobID一起下发,前端只标记对应的任务;
+// 流程中已推送过更具体的原因时不再覆盖
+func EmitFailed(ctx context.Context, msg string) {
+	if job := JobFromContext(ctx); job != nil {
+		if last, ok := LastProgress(job.ID); ok && last.Phase == PhaseFailed {
+			return
+		}
+	}
+	EmitProgress(ctx, Progress{Phase: PhaseFailed, MessageKey: MsgInstallFailed, Params: map[string]string{"error": msg}, Error: msg})
+}
+
+// 推送不影响任务继续执行的警告,阶段保持不变
+func emitWarning(ctx context.Context, phase Phase, msg string) {
+	EmitProgress(ctx, Progress{Phase: phase, MessageKey: MsgWarning, Params: map[string]string{"error": msg}, Error: msg})
+}
+
+// 推送只有阶段与文案的进度
+func emitPhase(ctx context.Context, phase Phase, key string, params map[string]string) {
+	EmitProgress(ctx, Progress{Phase: phase, MessageKey: key, Params: params})
+}
+
+// 任务最后一次进度
+func LastProgress(jobID string) (Progress, bool) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p, ok := lastProgress[jobID]
+	return p, ok
+}
+
+// 任务结束后一段时间清理进度记录
+func forgetProgress(jobID string) {
+	time.AfterFunc(10*time.Minute, func() {
+		progressMu.Lock()
+		delete(lastProgress, jobID)
+		progressMu.Unlock()
+	})
+}
+
+// 下载速度估算,指数平滑避免抖动
+type rateMeter struct {
+	last     time.Time
+	lastDone int64
+	speed    float64
+}
+
+// 根据新的已完成字节数更新速度,返回速度与剩余秒数
+func (m *rateMeter) update(done, total int64) (float64, float64) {
+	now := time.Now()
+	if m.last.IsZero() {
+		m.last, m.lastDone = now, done
+		return 0, -1
+	}
+
+	elapsed := now.Sub(m.last).Seconds()
+	if elapsed > 0 {
+		current := float64(done-m.lastDone) / elapsed
+		if m.speed == 0 {
+			m.speed = current
+		} else {
+			m.speed = 0.3*current + 0.7*m.speed
+		}
+		m.last, m.lastDone = now, done
+	}
+
+	eta := -1.0
+	if m.speed > 0 && total > 0 {
+		eta = float64(total-done) / m.speed
+	}
+	return m.speed, eta
+}
+
+func percentString(done, total int64) string {
+	if total <= 0 {
+		return "0.00"
+	}
+	return fmt.Sprintf("%.2f", float64(done)/float64(total)*100)
+}
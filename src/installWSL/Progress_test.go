@@ -0,0 +1,85 @@
+package installWSL
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// 记录测试期间推送的前端事件
+type eventLog struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+type recordedEvent struct {
+	name string
+	data interface{}
+}
+
+func recordEvents(t *testing.T) *eventLog {
+	t.Helper()
+	log := &eventLog{}
+	prev := emitEvent
+	emitEvent = func(ctx context.Context, name string, data ...interface{}) {
+		log.mu.Lock()
+		defer log.mu.Unlock()
+		var d interface{}
+		if len(data) > 0 {
+			d = data[0]
+		}
+		log.events = append(log.events, recordedEvent{name, d})
+	}
+	t.Cleanup(func() { emitEvent = prev })
+	return log
+}
+
+// 按名称筛选的事件
+func (l *eventLog) named(name string) []interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []interface{}
+	for _, e := range l.events {
+		if e.name == name {
+			out = append(out, e.data)
+		}
+	}
+	return out
+}
+
+// 所有 install:progress 事件
+func (l *eventLog) progress() []Progress {
+	var out []Progress
+	for _, d := range l.named("install:progress") {
+		out = append(out, d.(Progress))
+	}
+	return out
+}
+
+// 错误只通过带JobID的 install:progress 推送,且同一任务只推送一次失败
+func TestEmitFailedScopedToJob(t *testing.T) {
+	events := recordEvents(t)
+	a, actx := newJob(context.Background(), "install", "Ubuntu")
+	_, bctx := newJob(context.Background(), "install", "Debian")
+
+	emitWarning(bctx, PhaseChecking, "列出发行版失败")
+	EmitFailed(actx, "下载中断")
+	// 任务结束时的兜底失败不覆盖具体原因
+	EmitFailed(actx, "context deadline exceeded")
+
+	if n := len(events.named("wsl-error")) + len(events.named("wsl-output")); n != 0 {
+		t.Fatalf("仍推送了 %d 个全局事件", n)
+	}
+	var failed []Progress
+	for _, p := range events.progress() {
+		if p.Phase == PhaseFailed {
+			failed = append(failed, p)
+		}
+	}
+	if len(failed) != 1 || failed[0].JobID != a.ID || failed[0].Error != "下载中断" {
+		t.Fatalf("失败事件 = %+v", failed)
+	}
+	if last, _ := LastProgress(a.ID); last.Error != "下载中断" {
+		t.Fatalf("最后进度 = %+v", last)
+	}
+}
@@ -0,0 +1,352 @@
+package installWSL
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	catalog "Golang-WSL-GUI/src/Catalog"
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 发行版的用户配置策略,以清单中 defaultUser 的取值为名
+type Strategy struct {
+	Name string
+	// 授予sudo权限的组
+	AdminGroup string
+	// 缺少sudo时的安装命令,为空表示不安装
+	InstallSudo []string
+	// 按优先级排列的登录shell
+	Shells []string
+	// 追加到 sudoers 配置中的行
+	Sudoers []string
+}
+
+// 安装软件包可能需要联网下载,单独放宽超时
+const installSudoTimeout = 10 * time.Minute
+
+var defaultShells = []string{"/bin/bash", "/bin/sh"}
+
+var strategies = map[string]Strategy{
+	catalog.UserDebian: {
+		Name:        catalog.UserDebian,
+		AdminGroup:  "sudo",
+		InstallSudo: []string{"sh", "-c", "DEBIAN_FRONTEND=noninteractive apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y sudo"},
+	},
+	catalog.UserFedora: {
+		Name:        catalog.UserFedora,
+		AdminGroup:  "wheel",
+		InstallSudo: []string{"dnf", "install", "-y", "sudo"},
+	},
+	catalog.UserArch: {
+		Name:        catalog.UserArch,
+		AdminGroup:  "wheel",
+		InstallSudo: []string{"pacman", "-Sy", "--noconfirm", "sudo"},
+	},
+	catalog.UserSuse: {
+		Name:        catalog.UserSuse,
+		AdminGroup:  "wheel",
+		InstallSudo: []string{"zypper", "--non-interactive", "install", "sudo"},
+		// openSUSE 默认要求输入目标用户的密码
+		Sudoers: []string{"Defaults:%wheel !targetpw"},
+	},
+	catalog.UserAlpine: {
+		Name:        catalog.UserAlpine,
+		AdminGroup:  "wheel",
+		InstallSudo: []string{"apk", "add", "sudo"},
+		Shells:      []string{"/bin/bash", "/bin/ash", "/bin/sh"},
+	},
+}
+
+// os-release 中的 ID 对应的策略
+var osReleaseIDs = map[string]string{
+	"debian":              catalog.UserDebian,
+	"ubuntu":              catalog.UserDebian,
+	"kali":                catalog.UserDebian,
+	"fedora":              catalog.UserFedora,
+	"rhel":                catalog.UserFedora,
+	"centos":              catalog.UserFedora,
+	"rocky":               catalog.UserFedora,
+	"almalinux":           catalog.UserFedora,
+	"ol":                  catalog.UserFedora,
+	"arch":                catalog.UserArch,
+	"manjaro":             catalog.UserArch,
+	"suse":                catalog.UserSuse,
+	"opensuse":            catalog.UserSuse,
+	"opensuse-leap":       catalog.UserSuse,
+	"opensuse-tumbleweed": catalog.UserSuse,
+	"sles":                catalog.UserSuse,
+	"alpine":              catalog.UserAlpine,
+}
+
+// 未识别的发行版,管理员组按 /etc/group 决定
+const genericStrategy = "generic"
+
+// 确定策略: 清单中指定的策略优先,其次按 os-release 识别,最后应用清单中的覆盖项
+func resolveStrategy(distro string, osRelease map[string]string) Strategy {
+	entry, inCatalog := catalog.Current().Find(distro)
+	s, ok := Strategy{}, false
+	if inCatalog && entry.DefaultUser != catalog.UserAuto {
+		s, ok = strategies[entry.DefaultUser]
+	}
+	if !ok {
+		ids := append([]string{osRelease["ID"]}, strings.Fields(osRelease["ID_LIKE"])...)
+		for _, id := range ids {
+			if s, ok = strategies[osReleaseIDs[id]]; ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		s = Strategy{Name: genericStrategy}
+	}
+	if len(s.Shells) == 0 {
+		s.Shells = defaultShells
+	}
+	if inCatalog && entry.Provision != nil {
+		o := entry.Provision
+		if o.AdminGroup != "" {
+			s.AdminGroup = o.AdminGroup
+		}
+		if len(o.InstallSudo) > 0 {
+			s.InstallSudo = o.InstallSudo
+		}
+		if len(o.Shells) > 0 {
+			s.Shells = o.Shells
+		}
+		s.Sudoers = append(append([]string{}, s.Sudoers...), o.Sudoers...)
+	}
+	return s
+}
+
+// 解析 os-release,值两侧的引号会被去掉
+func parseOSRelease(text string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		fields[k] = strings.Trim(v, `"'`)
+	}
+	return fields
+}
+
+// 按行取出 /etc/shells 中的路径或 /etc/group 中的组名
+func parseNames(text string, sep string) map[string]bool {
+	names := map[string]bool{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if sep != "" {
+			line, _, _ = strings.Cut(line, sep)
+		}
+		names[line] = true
+	}
+	return names
+}
+
+// 探测可用命令与shell,shell路径作为参数传入,不拼接进脚本
+const probeScript = `for c in useradd sudo; do command -v "$c" >/dev/null 2>&1 && echo "cmd:$c"; done; for s in "$@"; do [ -x "$s" ] && echo "shell:$s"; done; true`
+
+// 探测到的发行版环境
+type provisionEnv struct {
+	Strategy Strategy
+	// os-release 中的 PRETTY_NAME
+	OS string
+	// 选定的登录shell
+	Shell string
+	// 选定的管理员组
+	Group      string
+	HasGroup   bool
+	HasUseradd bool
+	HasSudo    bool
+
+	// 未探测,用于预演时按条件列出所有步骤
+	unknown bool
+}
+
+// 读取 os-release、/etc/shells、/etc/group 并探测可用命令
+func probeProvision(ctx context.Context, cli wslcli.Client, name string) (provisionEnv, error) {
+	read := func(path string) string {
+		res, err := cli.Exec(ctx, name, wslcli.ExecOptions{}, "cat", path)
+		if err != nil {
+			return ""
+		}
+		return res.Text()
+	}
+	osRelease := parseOSRelease(read("/etc/os-release"))
+	env := provisionEnv{Strategy: resolveStrategy(name, osRelease), OS: osRelease["PRETTY_NAME"]}
+	if env.OS == "" {
+		env.OS = osRelease["ID"]
+	}
+	shells := parseNames(read("/etc/shells"), "")
+	groups := parseNames(read("/etc/group"), ":")
+
+	args := append([]string{"sh", "-c", probeScript, "sh"}, env.Strategy.Shells...)
+	res, err := cli.Exec(ctx, name, wslcli.ExecOptions{}, args...)
+	if err != nil {
+		return env, err
+	}
+	for _, line := range strings.Split(res.Text(), "\n") {
+		kind, value, _ := strings.Cut(strings.TrimSpace(line), ":")
+		switch {
+		case kind == "cmd" && value == "useradd":
+			env.HasUseradd = true
+		case kind == "cmd" && value == "sudo":
+			env.HasSudo = true
+		case kind == "shell" && env.Shell == "" && (len(shells) == 0 || shells[value]):
+			env.Shell = value
+		}
+	}
+	if env.Shell == "" {
+		env.Shell = "/bin/sh"
+	}
+
+	env.Group = env.Strategy.AdminGroup
+	if env.Group == "" {
+		env.Group = "wheel"
+		if groups["sudo"] {
+			env.Group = "sudo"
+		}
+	}
+	env.HasGroup = groups[env.Group]
+	return env, nil
+}
+
+// 预演用的环境,所有依赖探测结果的步骤都带条件列出
+func plannedEnv(name string) provisionEnv {
+	s := resolveStrategy(name, nil)
+	group := s.AdminGroup
+	if group == "" {
+		group = "<sudo 或 wheel>"
+	}
+	return provisionEnv{Strategy: s, Shell: s.Shells[0], Group: group, unknown: true}
+}
+
+const (
+	sudoersFile = "/etc/sudoers.d/easy-wsl-gui"
+	sudoersTemp = "/etc/sudoers.d/.easy-wsl-gui.tmp"
+)
+
+// sudoers 配置内容
+func sudoersContent(env provisionEnv) string {
+	lines := append([]string{fmt.Sprintf("%%%s ALL=(ALL:ALL) ALL", env.Group)}, env.Strategy.Sudoers...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// 按探测结果生成配置用户的命令,执行与预演共用
+func provisionSteps(Info WSLinfo, env provisionEnv) []userStep {
+	user, pass, group := Info.Auth.User, Info.Auth.Password, env.Group
+	// env.unknown 时仍列出条件步骤
+	when := func(known bool, condition string) (bool, string) {
+		if env.unknown {
+			return true, condition
+		}
+		return known, ""
+	}
+	var steps []userStep
+
+	if ok, cond := when(!env.HasSudo, "发行版未安装sudo"); ok && len(env.Strategy.InstallSudo) > 0 {
+		steps = append(steps, userStep{
+			argv: env.Strategy.InstallSudo, key: MsgUserInstallSudo, desc: "安装sudo", condition: cond,
+			err: "安装sudo失败", timeout: installSudoTimeout, optional: true, installsSudo: true,
+		})
+	}
+	if ok, cond := when(!env.HasGroup, fmt.Sprintf("不存在 %s 组", group)); ok {
+		argv := []string{"groupadd", "-r", group}
+		if !env.HasUseradd && !env.unknown {
+			argv = []string{"addgroup", "-S", group}
+		}
+		steps = append(steps, userStep{argv: argv, key: MsgUserGroup, desc: fmt.Sprintf("创建 %s 组", group), condition: cond, err: "无法创建管理员组"})
+	}
+
+	create := userStep{argv: []string{"useradd", "-m", "-s", env.Shell, user}, key: MsgUserCreate, desc: fmt.Sprintf("创建用户 %s,登录shell为 %s", user, env.Shell), err: "用户名配置错误"}
+	join := userStep{argv: []string{"usermod", "-aG", group, user}, key: MsgUserGroup, desc: fmt.Sprintf("加入 %s 组", group), err: "无法配置用户Sudo权限"}
+	if !env.HasUseradd && !env.unknown {
+		// busybox 只提供 adduser/addgroup
+		create.argv = []string{"adduser", "-D", "-s", env.Shell, user}
+		join.argv = []string{"addgroup", user, group}
+	}
+	steps = append(steps,
+		create,
+		userStep{argv: []string{"chpasswd"}, stdin: user + ":" + pass + "\n", key: MsgUserPassword, desc: "设置用户密码", err: "密码配置错误"},
+		join,
+	)
+
+	// 先写临时文件,visudo 校验通过后再替换,避免写坏的配置导致sudo不可用
+	if ok, cond := when(env.HasSudo || len(env.Strategy.InstallSudo) > 0, "已安装sudo"); ok {
+		steps = append(steps,
+			userStep{
+				argv:  []string{"sh", "-c", "mkdir -p /etc/sudoers.d && umask 0337 && cat > " + sudoersTemp},
+				stdin: sudoersContent(env), key: MsgUserSudoers, desc: "写入sudoers配置到临时文件",
+				condition: cond, err: "无法写入sudoers配置", needsSudo: true,
+			},
+			userStep{
+				argv: []string{"visudo", "-cf", sudoersTemp}, key: MsgUserSudoers, desc: "校验sudoers配置",
+				condition: cond, err: "sudoers配置校验失败", needsSudo: true, cleanup: []string{"rm", "-f", sudoersTemp},
+			},
+			userStep{
+				argv: []string{"mv", "-f", sudoersTemp, sudoersFile}, key: MsgUserSudoers, desc: "启用sudoers配置 " + sudoersFile,
+				condition: conditionIf(env.unknown, "已安装sudo,且 visudo 校验通过"), err: "无法启用sudoers配置", needsSudo: true,
+			},
+		)
+	}
+
+	steps = append(steps, userStep{
+		apply: func(ctx context.Context, cli wslcli.Client, name string) error {
+			return wslcli.UpdateWslConf(ctx, cli, name, func(conf *wslcli.WslConf) {
+				conf.Set("user", "default", user)
+			})
+		},
+		key: MsgUserDefault, desc: "更新 /etc/wsl.conf 中的默认用户", err: "无法配置默认用户",
+	})
+	return steps
+}
+
+func conditionIf(ok bool, condition string) string {
+	if ok {
+		return condition
+	}
+	return ""
+}
+
+// 依次执行配置步骤,安装sudo失败时跳过sudoers配置
+func runProvision(ctx context.Context, cli wslcli.Client, Info WSLinfo, env provisionEnv) error {
+	hasSudo := env.HasSudo
+	for _, step := range provisionSteps(Info, env) {
+		if step.needsSudo && !hasSudo {
+			continue
+		}
+		emitPhase(ctx, PhaseConfiguringUser, step.key, map[string]string{"user": Info.Auth.User, "group": env.Group, "shell": env.Shell})
+		_, err := step.run(ctx, cli, Info.Linux_Version)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			if step.installsSudo {
+				hasSudo = true
+			}
+			continue
+		}
+		if len(step.cleanup) > 0 {
+			cli.Exec(ctx, Info.Linux_Version, wslcli.ExecOptions{User: "root"}, step.cleanup...)
+			// 校验失败时不再启用配置
+			hasSudo = false
+		}
+		if step.optional || step.needsSudo {
+			emitWarning(ctx, PhaseConfiguringUser, fmt.Sprintf("%s,已跳过: %s", step.err, wslcli.Describe(err)))
+			continue
+		}
+		EmitFailed(ctx, fmt.Sprintf("%s: %s", step.err, wslcli.Describe(err)))
+		return fmt.Errorf("%s: %w", step.err, err)
+	}
+	return nil
+}
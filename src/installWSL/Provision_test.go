@@ -0,0 +1,103 @@
+package installWSL
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 按发行版族选择建用户与加组的命令
+func TestProvisionByDistro(t *testing.T) {
+	cases := []struct {
+		name, osRelease, tools, group string
+		want                          [][]string
+	}{
+		{
+			name:      "Debian",
+			osRelease: "ID=ubuntu\nID_LIKE=debian\n",
+			tools:     "cmd:useradd\nshell:/bin/bash\n",
+			group:     "root:x:0:\nsudo:x:27:\n",
+			want:      [][]string{{"useradd", "-m", "-s", "/bin/bash", "bob"}, {"usermod", "-aG", "sudo", "bob"}},
+		},
+		{
+			name:      "Alpine",
+			osRelease: "ID=alpine\n",
+			tools:     "shell:/bin/ash\nshell:/bin/sh\n",
+			group:     "root:x:0:\n",
+			want:      [][]string{{"apk", "add", "sudo"}, {"addgroup", "-S", "wheel"}, {"adduser", "-D", "-s", "/bin/ash", "bob"}, {"addgroup", "bob", "wheel"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recordEvents(t)
+			f := fakeDefault(t)
+			f.On("-d", c.name, "...")
+			f.On("-d", c.name, "--exec", "cat", "/etc/os-release").Reply(wslcli.FakeResponse{Stdout: c.osRelease})
+			f.On("-d", c.name, "--exec", "cat", "/etc/group").Reply(wslcli.FakeResponse{Stdout: c.group})
+			f.On("-d", c.name, "--exec", "sh", "-c", probeScript, "...").Reply(wslcli.FakeResponse{Stdout: c.tools})
+			f.On("--terminate", c.name)
+
+			info := WSLinfo{Linux_Version: c.name, Auth: &WSLAuth{User: "bob", Password: "pw"}}
+			if err := WSL2_Setting_User(context.Background(), info); err != nil {
+				t.Fatal(err)
+			}
+			for _, argv := range c.want {
+				if f.Called(append([]string{"-d", c.name, "-u", "root", "--exec"}, argv...)...) != 1 {
+					t.Fatalf("没有执行 %v", argv)
+				}
+			}
+		})
+	}
+}
+
+// visudo 校验失败时删除临时文件,不启用sudoers配置
+func TestProvisionSudoersRejected(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("-d", "Ubuntu", "...")
+	f.On("-d", "Ubuntu", "--exec", "sh", "-c", probeScript, "...").Reply(wslcli.FakeResponse{Stdout: "cmd:useradd\ncmd:sudo\nshell:/bin/bash\n"})
+	f.On("-d", "Ubuntu", "-u", "root", "--exec", "visudo", "...").Reply(wslcli.FakeResponse{ExitCode: 1, Stdout: "parse error"})
+	f.On("--terminate", "Ubuntu")
+
+	WSL2_Setting_User(context.Background(), WSLinfo{Linux_Version: "Ubuntu", Auth: &WSLAuth{User: "bob", Password: "pw"}})
+	if f.Called("-d", "Ubuntu", "-u", "root", "--exec", "rm", "-f", sudoersTemp) != 1 {
+		t.Fatal("校验失败后没有删除临时文件")
+	}
+	if f.Called("-d", "Ubuntu", "-u", "root", "--exec", "mv", "...") != 0 {
+		t.Fatal("校验失败后仍启用了sudoers配置")
+	}
+}
+
+// 迁移后只改写 wsl.conf 的默认用户,不重新创建用户
+func TestRestoreUserStep(t *testing.T) {
+	f := fakeDefault(t)
+	f.On("-d", "Ubuntu", "...")
+	f.On("-d", "Ubuntu", "--exec", "sh", "-c", "*").Reply(wslcli.FakeResponse{Stdout: "[boot]\nsystemd=true\n"})
+
+	step := restoreUserStep(WSLinfo{Linux_Version: "Ubuntu", Auth: &WSLAuth{User: "bob"}})
+	if _, err := step.run(context.Background(), wslcli.Default, "Ubuntu"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range f.Calls() {
+		if strings.Contains(strings.Join(c.Args, " "), "useradd") {
+			t.Fatalf("迁移时重新创建了用户: %v", c.Args)
+		}
+	}
+	calls := f.Calls()
+	last := calls[len(calls)-1]
+	if !strings.Contains(last.Stdin, "systemd=true") || !strings.Contains(last.Stdin, "[user]\ndefault=bob") {
+		t.Fatalf("写入的 wsl.conf = %q", last.Stdin)
+	}
+}
+
+func TestPlanMigrationRestoresUser(t *testing.T) {
+	p := NewPlan("migration", "Ubuntu")
+	PlanMigration(p, WSLinfo{Linux_Version: "Ubuntu", Install_Path: &WSLpath{Path: t.TempDir()}, Auth: &WSLAuth{User: "bob"}}, nil)
+	for _, s := range p.Steps {
+		if strings.Contains(strings.Join(s.Command, " "), "useradd") {
+			t.Fatalf("迁移计划中重新创建了用户: %v", s.Command)
+		}
+	}
+}
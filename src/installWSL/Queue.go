@@ -0,0 +1,244 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	// 默认同时执行的安装任务数
+	defaultParallelism = 2
+	maxParallelism     = 8
+	// 保留的已结束任务数
+	maxHistory = 20
+)
+
+var ErrAlreadyQueued = errors.New("该发行版已在安装队列中")
+
+// 安装队列,下载等步骤按并行上限同时执行,wsl --import 始终串行
+type Queue struct {
+	// 队列变化时回调,在锁外调用
+	OnChange func([]JobInfo)
+
+	mu      sync.Mutex
+	limit   int
+	pending []*queuedJob
+	running []*Job
+	history []*Job
+}
+
+type queuedJob struct {
+	job  *Job
+	ctx  context.Context
+	run  func(ctx context.Context) error
+	done func(job *Job, ctx context.Context, err error)
+}
+
+var DefaultQueue = NewQueue(defaultParallelism)
+
+// 导入槽位,同一时间只允许一个 wsl --import
+var importSlot = make(chan struct{}, 1)
+
+func NewQueue(limit int) *Queue {
+	return &Queue{limit: clampParallelism(limit)}
+}
+
+func clampParallelism(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if n > maxParallelism {
+		return maxParallelism
+	}
+	return n
+}
+
+// 加入队列,run在轮到时执行,结束或排队中被取消后调用done;
+// 调用done时ctx只有在任务被取消时才已结束
+func (q *Queue) Submit(parent context.Context, kind, distro string, run func(ctx context.Context) error, done func(job *Job, ctx context.Context, err error)) (*Job, error) {
+	q.mu.Lock()
+	for _, j := range q.activeLocked() {
+		if strings.EqualFold(j.Distro, distro) {
+			q.mu.Unlock()
+			return nil, ErrAlreadyQueued
+		}
+	}
+
+	job, ctx := newJob(parent, kind, distro)
+	job.queue = q
+	q.pending = append(q.pending, &queuedJob{job: job, ctx: ctx, run: run, done: done})
+	q.dispatchLocked()
+	q.mu.Unlock()
+
+	q.changed()
+	return job, nil
+}
+
+// 取消任务,排队中的直接移出队列,执行中的结束正在运行的命令
+func (q *Queue) Cancel(id string) error {
+	q.mu.Lock()
+	for i, qj := range q.pending {
+		if qj.job.ID == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.mu.Unlock()
+
+			qj.job.cancel()
+			q.finish(qj, context.Canceled)
+			return nil
+		}
+	}
+	for _, j := range q.running {
+		if j.ID == id {
+			q.mu.Unlock()
+			j.cancel()
+			return nil
+		}
+	}
+	q.mu.Unlock()
+	return ErrJobNotFound
+}
+
+// 调整排队中任务的位置
+func (q *Queue) Reorder(id string, index int) error {
+	q.mu.Lock()
+	from := -1
+	for i, qj := range q.pending {
+		if qj.job.ID == id {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		q.mu.Unlock()
+		return errors.New("只能调整排队中的任务")
+	}
+
+	qj := q.pending[from]
+	q.pending = append(q.pending[:from], q.pending[from+1:]...)
+	index = max(0, min(index, len(q.pending)))
+	q.pending = append(q.pending[:index], append([]*queuedJob{qj}, q.pending[index:]...)...)
+	q.mu.Unlock()
+
+	q.changed()
+	return nil
+}
+
+// 设置并行上限,返回实际生效的值
+func (q *Queue) SetLimit(n int) int {
+	q.mu.Lock()
+	q.limit = clampParallelism(n)
+	q.dispatchLocked()
+	limit := q.limit
+	q.mu.Unlock()
+
+	q.changed()
+	return limit
+}
+
+func (q *Queue) Limit() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.limit
+}
+
+// 执行中、排队中及最近结束的任务
+func (q *Queue) List() []JobInfo {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	list := make([]JobInfo, 0, len(q.running)+len(q.pending)+len(q.history))
+	for _, j := range q.running {
+		list = append(list, j.info(-1))
+	}
+	for i, qj := range q.pending {
+		list = append(list, qj.job.info(i))
+	}
+	for i := len(q.history) - 1; i >= 0; i-- {
+		list = append(list, q.history[i].info(-1))
+	}
+	return list
+}
+
+// 清除已结束的任务记录
+func (q *Queue) ClearFinished() {
+	q.mu.Lock()
+	q.history = nil
+	q.mu.Unlock()
+	q.changed()
+}
+
+func (q *Queue) activeLocked() []*Job {
+	active := append([]*Job{}, q.running...)
+	for _, qj := range q.pending {
+		active = append(active, qj.job)
+	}
+	return active
+}
+
+// 在并行上限内启动排队中的任务
+func (q *Queue) dispatchLocked() {
+	for len(q.running) < q.limit && len(q.pending) > 0 {
+		qj := q.pending[0]
+		q.pending = q.pending[1:]
+		q.running = append(q.running, qj.job)
+		qj.job.setRunning()
+
+		go func() {
+			err := qj.run(qj.ctx)
+			q.mu.Lock()
+			for i, j := range q.running {
+				if j == qj.job {
+					q.running = append(q.running[:i], q.running[i+1:]...)
+					break
+				}
+			}
+			q.mu.Unlock()
+			q.finish(qj, err)
+		}()
+	}
+}
+
+// 记录任务结果,回调后继续调度
+func (q *Queue) finish(qj *queuedJob, err error) {
+	// setFinished 会取消ctx,回调需在此之前执行,才能由ctx区分失败与取消
+	ctxErr := qj.ctx.Err()
+	if qj.done != nil {
+		qj.done(qj.job, qj.ctx, err)
+	}
+	qj.job.setFinished(ctxErr, err)
+
+	q.mu.Lock()
+	q.history = append(q.history, qj.job)
+	if len(q.history) > maxHistory {
+		q.history = q.history[len(q.history)-maxHistory:]
+	}
+	q.dispatchLocked()
+	q.mu.Unlock()
+
+	q.changed()
+}
+
+func (q *Queue) changed() {
+	if q.OnChange != nil {
+		q.OnChange(q.List())
+	}
+}
+
+// 等待导入槽位,返回释放函数
+func acquireImport(ctx context.Context) (func(), error) {
+	select {
+	case importSlot <- struct{}{}:
+		return func() { <-importSlot }, nil
+	default:
+	}
+
+	emitPhase(ctx, PhaseImporting, MsgImportWaiting, nil)
+	select {
+	case importSlot <- struct{}{}:
+		return func() { <-importSlot }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
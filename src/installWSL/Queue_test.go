@@ -0,0 +1,121 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 并发为1时按队列顺序执行,支持调整顺序与取消排队中的任务
+func TestQueueOrderAndCancel(t *testing.T) {
+	q := NewQueue(1)
+	var mu sync.Mutex
+	var order []string
+	gate := make(chan struct{})
+	var wg sync.WaitGroup
+	run := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			select {
+			case <-gate:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	done := func(*Job, context.Context, error) { wg.Done() }
+
+	wg.Add(4)
+	a, _ := q.Submit(context.Background(), "install", "A", run("A"), done)
+	_, _ = q.Submit(context.Background(), "install", "B", run("B"), done)
+	c, _ := q.Submit(context.Background(), "install", "C", run("C"), done)
+	d, _ := q.Submit(context.Background(), "install", "D", run("D"), done)
+	// 发行版名称不区分大小写
+	if _, err := q.Submit(context.Background(), "install", "a", run("a"), done); !errors.Is(err, ErrAlreadyQueued) {
+		t.Fatalf("err = %v, 期望 ErrAlreadyQueued", err)
+	}
+
+	if err := q.Reorder(c.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Cancel(d.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Cancel(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(gate)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 || order[0] != "A" || order[1] != "C" || order[2] != "B" {
+		t.Fatalf("执行顺序 = %v", order)
+	}
+	if a.Status() != JobCancelled || c.Status() != JobDone {
+		t.Fatalf("状态 A=%s C=%s", a.Status(), c.Status())
+	}
+	// 排队中取消的任务不会启动
+	if d.Status() != JobCancelled || d.Started() {
+		t.Fatalf("D 状态 = %s, 已启动 = %v", d.Status(), d.Started())
+	}
+}
+
+// 与 app.go 中安装任务的回调相同: 取消时推送 cancelled,失败时推送 failed
+func installDone(wg *sync.WaitGroup) func(*Job, context.Context, error) {
+	return func(job *Job, ctx context.Context, err error) {
+		defer wg.Done()
+		switch {
+		case err == nil:
+		case ctx.Err() != nil:
+			EmitProgress(ctx, Progress{Phase: PhaseCancelled, MessageKey: MsgInstallCancel})
+		default:
+			EmitFailed(ctx, err.Error())
+		}
+	}
+}
+
+// 普通失败的任务走失败分支,不会被当作取消而清理
+func TestQueueFailedNotCancelled(t *testing.T) {
+	events := recordEvents(t)
+	q := NewQueue(2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	failed, _ := q.Submit(context.Background(), "install", "A", func(ctx context.Context) error {
+		return errors.New("下载失败")
+	}, installDone(&wg))
+	gate := make(chan struct{})
+	cancelled, _ := q.Submit(context.Background(), "install", "B", func(ctx context.Context) error {
+		close(gate)
+		<-ctx.Done()
+		return ctx.Err()
+	}, installDone(&wg))
+	<-gate
+	if err := q.Cancel(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if failed.Status() != JobFailed || cancelled.Status() != JobCancelled {
+		t.Fatalf("状态 A=%s B=%s", failed.Status(), cancelled.Status())
+	}
+	phases := map[string]Phase{}
+	for _, p := range events.progress() {
+		phases[p.JobID] = p.Phase
+		if p.JobID == failed.ID && p.Phase == PhaseFailed && p.Error != "下载失败" {
+			t.Fatalf("失败原因 = %q", p.Error)
+		}
+	}
+	if phases[failed.ID] != PhaseFailed {
+		t.Fatalf("失败任务的最后阶段 = %s", phases[failed.ID])
+	}
+	if phases[cancelled.ID] != PhaseCancelled {
+		t.Fatalf("取消任务的最后阶段 = %s", phases[cancelled.ID])
+	}
+}
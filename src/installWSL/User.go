@@ -0,0 +1,164 @@
+package installWSL
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 校验失败的字段
+const (
+	FieldUsername = "username"
+	FieldPassword = "password"
+)
+
+// 用户名或密码不符合要求,在执行任何命令前返回
+type ValidationError struct {
+	Field string
+	// 不符合的原因
+	Reason string
+	// 校验用户名时使用的规则
+	Pattern string
+}
+
+func (e *ValidationError) Error() string {
+	switch e.Field {
+	case FieldUsername:
+		return "用户名无效: " + e.Reason
+	case FieldPassword:
+		return "密码无效: " + e.Reason
+	}
+	return e.Reason
+}
+
+const (
+	// adduser 的默认 NAME_REGEX
+	defaultNameRegex = `^[a-z][-a-z0-9_]*\$?$`
+	// useradd 允许的最长用户名
+	maxUsernameLen = 32
+)
+
+var nameRegexLine = regexp.MustCompile(`^\s*NAME_REGEX\s*=\s*(.*?)\s*$`)
+
+// 按规则校验用户名,pattern为空或无法编译时使用默认规则
+func ValidateUsername(user, pattern string) error {
+	if user == "" {
+		return &ValidationError{Field: FieldUsername, Reason: "不能为空"}
+	}
+	if len(user) > maxUsernameLen {
+		return &ValidationError{Field: FieldUsername, Reason: fmt.Sprintf("长度不能超过 %d 个字符", maxUsernameLen)}
+	}
+	if user == "root" {
+		return &ValidationError{Field: FieldUsername, Reason: "不能使用 root"}
+	}
+	re, err := regexp.Compile(pattern)
+	if pattern == "" || err != nil {
+		pattern = defaultNameRegex
+		re = regexp.MustCompile(pattern)
+	}
+	if !re.MatchString(user) {
+		return &ValidationError{Field: FieldUsername, Reason: fmt.Sprintf("必须匹配 %s", pattern), Pattern: pattern}
+	}
+	return nil
+}
+
+// 校验密码,chpasswd 按行读取,不能包含换行
+func ValidatePassword(pass string) error {
+	if pass == "" {
+		return &ValidationError{Field: FieldPassword, Reason: "不能为空"}
+	}
+	if strings.ContainsAny(pass, "\r\n\x00") {
+		return &ValidationError{Field: FieldPassword, Reason: "不能包含换行或空字符"}
+	}
+	return nil
+}
+
+// 用默认规则校验账户,用于提交安装前
+func ValidateCredentials(user, pass string) error {
+	if err := ValidateUsername(user, ""); err != nil {
+		return err
+	}
+	return ValidatePassword(pass)
+}
+
+// 读取发行版 /etc/adduser.conf 中的 NAME_REGEX,没有配置时返回空;
+// 与其他配置步骤一样以root读取,此时默认用户可能还不存在
+func nameRegex(ctx context.Context, cli wslcli.Client, name string) string {
+	res, err := cli.Exec(ctx, name, wslcli.ExecOptions{User: "root"}, "cat", "/etc/adduser.conf")
+	if err != nil {
+		return ""
+	}
+	return parseNameRegex(res.Text())
+}
+
+func parseNameRegex(conf string) string {
+	pattern := ""
+	for _, line := range strings.Split(conf, "\n") {
+		if m := nameRegexLine.FindStringSubmatch(line); m != nil {
+			pattern = strings.Trim(m[1], `"'`)
+		}
+	}
+	return pattern
+}
+
+// 按发行版的规则校验账户
+func validateUser(ctx context.Context, cli wslcli.Client, Info WSLinfo) error {
+	if err := ValidateUsername(Info.Auth.User, nameRegex(ctx, cli, Info.Linux_Version)); err != nil {
+		return err
+	}
+	return ValidatePassword(Info.Auth.Password)
+}
+
+// 配置用户的一步,以root身份直接执行,不经过shell
+type userStep struct {
+	argv []string
+	// 通过标准输入传入的内容,如口令
+	stdin string
+	// 进度文案键
+	key string
+	// 预演中的说明与执行条件
+	desc      string
+	condition string
+	err       string
+	// 覆盖默认超时
+	timeout time.Duration
+	// 失败时跳过,不中止配置
+	optional bool
+	// 成功后发行版中有sudo可用
+	installsSudo bool
+	// 仅在有sudo时执行
+	needsSudo bool
+	// 失败后执行的清理命令
+	cleanup []string
+	// 不能用单条命令完成的步骤,设置后忽略argv
+	apply func(ctx context.Context, cli wslcli.Client, name string) error
+}
+
+// 执行一步
+func (s userStep) run(ctx context.Context, cli wslcli.Client, name string) (wslcli.Result, error) {
+	if s.apply != nil {
+		return wslcli.Result{}, s.apply(ctx, cli, name)
+	}
+	opts := wslcli.ExecOptions{User: "root", Timeout: s.timeout}
+	if s.stdin != "" {
+		opts.Stdin = strings.NewReader(s.stdin)
+	}
+	return cli.Exec(ctx, name, opts, s.argv...)
+}
+
+// 迁移后还原默认用户: 用户随tar一并导入,只需在 wsl.conf 中重新指定
+func restoreUserStep(Info WSLinfo) userStep {
+	user := Info.Auth.User
+	return userStep{
+		apply: func(ctx context.Context, cli wslcli.Client, name string) error {
+			return wslcli.UpdateWslConf(ctx, cli, name, func(conf *wslcli.WslConf) {
+				conf.Set("user", "default", user)
+			})
+		},
+		desc: "在 /etc/wsl.conf 中还原默认用户 " + user, err: "还原默认用户出现问题",
+	}
+}
@@ -0,0 +1,87 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+func TestParseNameRegex(t *testing.T) {
+	cases := []struct {
+		name, conf, want string
+	}{
+		{"unset", "DSHELL=/bin/bash\n", ""},
+		{"quoted", "NAME_REGEX=\"^[a-z][a-z0-9]*$\"\n", "^[a-z][a-z0-9]*$"},
+		{"single quoted", "NAME_REGEX = '^[a-z]+$'\n", "^[a-z]+$"},
+		{"last wins", "NAME_REGEX=\"^a$\"\nNAME_REGEX=\"^b$\"\n", "^b$"},
+		{"commented", "NAME_REGEX=\"^a$\"\n#NAME_REGEX=\"^b$\"\n  # NAME_REGEX=\"^c$\"\n", "^a$"},
+		{"crlf", "NAME_REGEX=\"^a$\"\r\n", "^a$"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := parseNameRegex(c.conf); got != c.want {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	cases := []struct {
+		user, pattern string
+		ok            bool
+	}{
+		{"bob", "", true},
+		{"Bob", "", false},
+		{"", "", false},
+		{"root", "", false},
+		{strings.Repeat("b", maxUsernameLen+1), "", false},
+		{"bob;rm", "", false},
+		// 发行版允许大写
+		{"Bob", `^[A-Za-z][-A-Za-z0-9_]*$`, true},
+		{"bob", `^[A-Z]+$`, false},
+		// 无法编译时使用默认规则
+		{"bob", `^[a-z(`, true},
+		{"Bob", `^[a-z(`, false},
+	}
+	for _, c := range cases {
+		err := ValidateUsername(c.user, c.pattern)
+		if (err == nil) != c.ok {
+			t.Errorf("ValidateUsername(%q, %q) = %v", c.user, c.pattern, err)
+		}
+		var ve *ValidationError
+		if err != nil && (!errors.As(err, &ve) || ve.Field != FieldUsername) {
+			t.Errorf("ValidateUsername(%q) 错误类型 = %T", c.user, err)
+		}
+	}
+	// 报告实际使用的规则
+	var ve *ValidationError
+	if errors.As(ValidateUsername("Bob", `^[a-z(`), &ve) && ve.Pattern != defaultNameRegex {
+		t.Fatalf("Pattern = %q, 期望默认规则", ve.Pattern)
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	for _, pass := range []string{"", "a\rb", "a\nb", "a\x00b"} {
+		var ve *ValidationError
+		if err := ValidatePassword(pass); !errors.As(err, &ve) || ve.Field != FieldPassword {
+			t.Errorf("ValidatePassword(%q) = %v", pass, err)
+		}
+	}
+	// 引号与shell元字符不影响 chpasswd
+	if err := ValidatePassword(`a'b"c$(x) ;|`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 以root读取 adduser.conf,默认用户此时可能还不存在
+func TestNameRegexAsRoot(t *testing.T) {
+	f := wslcli.NewFakeRunner()
+	f.On("-d", "Ubuntu", "-u", "root", "--exec", "cat", "/etc/adduser.conf").Reply(wslcli.FakeResponse{Stdout: "NAME_REGEX=\"^x$\"\n"})
+	if got := nameRegex(context.Background(), wslcli.New(f), "Ubuntu"); got != "^x$" {
+		t.Fatalf("nameRegex = %q, calls = %v", got, f.Calls())
+	}
+}
@@ -0,0 +1,339 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	catalog "Golang-WSL-GUI/src/Catalog"
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 安装WSL发行版信息
+type WSLinfo struct {
+	Linux_Version   string
+	Install_Path    *WSLpath
+	Auth            *WSLAuth
+	DownloadThreads *WSLDownload
+	// 本地镜像路径,非空时跳过下载直接导入
+	Local_File string
+}
+
+type WSLpath struct {
+	Path string
+}
+
+type WSLAuth struct {
+	User     string
+	Password string
+}
+
+type WSLDownload struct {
+	DownloadThreads int
+	// 首选镜像主机关键字,如 tuna、ustc
+	PreferredMirror string
+}
+
+// 迁移后等待发行版启动的时间
+const migrationStartDelay = 10 * time.Second
+
+// 拼接路径字符串
+func FilePath_string(Info WSLinfo) string {
+	if Info.Local_File != "" {
+		return Info.Local_File
+	}
+	fileName := Info.Linux_Version
+	// 根据DownloadThreads是否是空指针判断是安装还是迁移
+	if Info.DownloadThreads != nil {
+		// 文件名拼凑
+		image, _ := catalog.Current().Find(Info.Linux_Version)
+		if len(image.URLs) > 0 && strings.Contains(image.URLs[0], ".wsl") {
+			fileName += ".wsl"
+		}
+	} else {
+		// 迁移后缀
+		fileName += ".tar"
+	}
+
+	return filepath.Join(Info.Install_Path.Path, fileName)
+}
+
+// 检查发行版是否已安装
+func WSL2_Check(ctx context.Context, Info WSLinfo) error {
+	emitPhase(ctx, PhaseChecking, MsgCheckStart, nil)
+	distros, err := wslcli.Default.List(ctx)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		emitWarning(ctx, PhaseChecking, fmt.Sprintf("在检查步骤出错: %s", wslcli.Describe(err)))
+	}
+	for _, d := range distros {
+		if !strings.EqualFold(d.Name, Info.Linux_Version) {
+			continue
+		}
+		if conf, err := wslcli.ReadWslConf(ctx, wslcli.Default, d.Name); err == nil {
+			if _, ok := conf.Get("user", "default"); ok {
+				EmitFailed(ctx, fmt.Sprintf("该发行版 %s 已经安装在Windows上", Info.Linux_Version))
+				return errors.New("发行版已存在")
+			}
+		}
+		emitPhase(ctx, PhaseConfiguringUser, MsgCheckExisting, nil)
+		return errors.New("发行版存在,但未配置默认用户")
+	}
+	return nil
+}
+
+func WSL2_Downloader(ctx context.Context, Info WSLinfo) error {
+	if err := WSL2_Check(ctx, Info); err != nil {
+		return err
+	}
+
+	image, ok := catalog.Current().Find(Info.Linux_Version)
+	if !ok || len(image.URLs) == 0 {
+		EmitFailed(ctx, fmt.Sprintf("发行版清单中没有 %s", Info.Linux_Version))
+		return errors.New("发行版清单中没有该发行版")
+	}
+
+	fullpath := FilePath_string(Info)
+
+	// 创建目标目录
+	if os.MkdirAll(filepath.Dir(fullpath), 0755) != nil {
+		EmitFailed(ctx, fmt.Sprintf("在路径 %s 创建安装文件失败", fullpath))
+		return errors.New("创建文件失败")
+	}
+
+	// 优先使用本地缓存
+	if DefaultCache.Restore(ctx, image.Sha256, fullpath) == nil {
+		emitPhase(ctx, PhaseDownloading, MsgCacheHit, nil)
+		emitPhase(ctx, PhaseDownloading, MsgDownloadDone, nil)
+		return nil
+	}
+
+	threads, prefer := 1, ""
+	if Info.DownloadThreads != nil {
+		threads = Info.DownloadThreads.DownloadThreads
+		prefer = Info.DownloadThreads.PreferredMirror
+	}
+
+	var meter rateMeter
+	err := DownloadFile(ctx, DownloadTask{
+		URLs:            image.URLs,
+		FilePath:        fullpath,
+		Sha256:          image.Sha256,
+		Threads:         threads,
+		PreferredMirror: prefer,
+		Progress: func(done, total int64) {
+			speed, eta := meter.update(done, total)
+			EmitProgress(ctx, Progress{
+				Phase:      PhaseDownloading,
+				BytesDone:  done,
+				BytesTotal: total,
+				Speed:      speed,
+				ETA:        eta,
+				MessageKey: MsgDownloadRunning,
+				Params:     map[string]string{"percent": percentString(done, total)},
+			})
+		},
+		Resume: func(done, total int64) {
+			EmitProgress(ctx, Progress{
+				Phase:      PhaseDownloading,
+				BytesDone:  done,
+				BytesTotal: total,
+				MessageKey: MsgDownloadResume,
+				Params:     map[string]string{"percent": percentString(done, total)},
+			})
+		},
+		Mirror: func(url string) {
+			emitPhase(ctx, PhaseDownloading, MsgDownloadMirror, map[string]string{"host": mirrorHost(url)})
+		},
+		Verifying: func() {
+			emitPhase(ctx, PhaseVerifying, MsgVerifySha256, nil)
+		},
+	})
+	if ctx.Err() != nil {
+		// 取消时不保留残缺文件
+		RemoveDownload(fullpath)
+		return ctx.Err()
+	}
+	if errors.Is(err, ErrChecksum) {
+		// 如果校验失败，删除残缺文件
+		RemoveDownload(fullpath)
+		EmitFailed(ctx, "Sha256校验失败,请重新执行")
+		return err
+	}
+	if err != nil {
+		// 已下载部分保留,下次安装时继续
+		EmitFailed(ctx, fmt.Sprintf("下载 %s 发行版中断,请检查网络连接后重试: %v", Info.Linux_Version, err))
+		return err
+	}
+
+	// 存入缓存,失败不影响安装
+	if err := DefaultCache.Store(image.Sha256, Info.Linux_Version, fullpath); err == nil {
+		DefaultCache.Evict()
+	}
+
+	emitPhase(ctx, PhaseDownloading, MsgDownloadDone, nil)
+
+	return nil
+}
+
+func WSL2_Installer(ctx context.Context, Info WSLinfo) error {
+	if err := sleepContext(ctx, 2*time.Second); err != nil {
+		return err
+	}
+	// 多个任务同时安装时逐个导入
+	release, err := acquireImport(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	emitPhase(ctx, PhaseImporting, MsgImportStart, nil)
+	// 导入中途取消也可能留下注册信息,需要清理;
+	// 但只有确认同名发行版尚未注册时才标记,否则取消或清理会注销用户已有的发行版
+	if !distroRegistered(ctx, Info.Linux_Version) {
+		markImported(ctx)
+	}
+	err = wslcli.Default.Import(ctx, Info.Linux_Version, Info.Install_Path.Path, FilePath_string(Info), wslcli.ImportOptions{Version: 2})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		EmitFailed(ctx, fmt.Sprintf("解压安装出现错误: %s", wslcli.Describe(err)))
+		return err
+	}
+	emitPhase(ctx, PhaseImporting, MsgImportDone, nil)
+	return nil
+
+}
+
+// 发行版是否已注册,无法列出时视为已注册
+func distroRegistered(ctx context.Context, name string) bool {
+	distros, err := wslcli.Default.List(ctx)
+	if err != nil {
+		return true
+	}
+	for _, d := range distros {
+		if strings.EqualFold(d.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// 配置用户名,密码函数
+func WSL2_Setting_User(ctx context.Context, Info WSLinfo) error {
+	emitPhase(ctx, PhaseConfiguringUser, MsgConfigureUser, map[string]string{"user": Info.Auth.User})
+	// 在执行任何命令前按发行版规则校验
+	if err := validateUser(ctx, wslcli.Default, Info); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		EmitFailed(ctx, err.Error())
+		return err
+	}
+	// 按发行版选择管理员组、shell与sudo的安装方式
+	emitPhase(ctx, PhaseConfiguringUser, MsgUserDetect, nil)
+	env, err := probeProvision(ctx, wslcli.Default, Info.Linux_Version)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		EmitFailed(ctx, fmt.Sprintf("无法识别发行版环境: %s", wslcli.Describe(err)))
+		return err
+	}
+	emitPhase(ctx, PhaseConfiguringUser, MsgUserDetected, map[string]string{"os": env.OS, "strategy": env.Strategy.Name, "group": env.Group, "shell": env.Shell})
+	if err := runProvision(ctx, wslcli.Default, Info, env); err != nil {
+		return err
+	}
+
+	err = wslcli.Default.Terminate(ctx, Info.Linux_Version)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		emitWarning(ctx, PhaseConfiguringUser, fmt.Sprintf("暂停发行版出现错误: %s", wslcli.Describe(err)))
+	}
+	// 循环检测wsl发行版是否关停
+
+	return sleepContext(ctx, 2*time.Second)
+}
+
+// 清理被取消的安装任务: 注销本任务导入的发行版,删除下载的镜像
+// ctx 为已取消的任务ctx,仅沿用其中的审计发起者
+func CleanupInstall(ctx context.Context, Info WSLinfo, job *Job) {
+	if job != nil && job.Imported() {
+		ctx := context.WithoutCancel(ctx)
+		wslcli.Default.Terminate(ctx, Info.Linux_Version)
+		wslcli.Default.Unregister(ctx, Info.Linux_Version)
+	}
+	if Info.Local_File == "" {
+		RemoveDownload(FilePath_string(Info))
+	}
+}
+
+// 迁移发行版
+func MovingPathWSL(ctx context.Context, Info WSLinfo) error {
+	cli := wslcli.Default
+	emitEvent(ctx, "migration:progress", "正在导出发行版......")
+	// 导出
+	if err := cli.Export(ctx, Info.Linux_Version, FilePath_string(Info), wslcli.ExportOptions{}); err != nil {
+		emitEvent(ctx, "migration:done", map[string]interface{}{
+			"status": "failed",
+			"error":  fmt.Sprintf("导出出现问题: %s", wslcli.Describe(err)),
+		})
+		os.Remove(FilePath_string(Info))
+		return err
+	}
+	// 卸载
+	emitEvent(ctx, "migration:progress", "正在卸载发行版......")
+	if err := cli.Unregister(ctx, Info.Linux_Version); err != nil {
+		emitEvent(ctx, "migration:done", map[string]interface{}{
+			"status": "failed",
+			"error":  fmt.Sprintf("卸载出现问题: %s", wslcli.Describe(err)),
+		})
+		os.Remove(FilePath_string(Info))
+		return err
+	}
+	//导入
+	emitEvent(ctx, "migration:progress", "正在迁移发行版......")
+	if err := cli.Import(ctx, Info.Linux_Version, Info.Install_Path.Path, FilePath_string(Info), wslcli.ImportOptions{Version: 2}); err != nil {
+		emitEvent(ctx, "migration:done", map[string]interface{}{
+			"status": "failed",
+			"error":  fmt.Sprintf("导入出现问题: %s", wslcli.Describe(err)),
+		})
+		os.Remove(FilePath_string(Info))
+		return err
+	}
+	// 大致处理下
+	cli.Launch(ctx, Info.Linux_Version)
+	sleepContext(ctx, migrationStartDelay)
+	// 配置用户
+	emitEvent(ctx, "migration:progress", "正在还原用户配置......")
+	step := restoreUserStep(Info)
+	_, err := step.run(ctx, cli, Info.Linux_Version)
+	if err != nil {
+		emitEvent(ctx, "migration:done", map[string]interface{}{
+			"status": "failed",
+			"error":  fmt.Sprintf("%s: %s", step.err, wslcli.Describe(err)),
+		})
+		return err
+	}
+	// 发送完成信息
+	emitEvent(ctx, "migration:done", map[string]interface{}{
+		"status": "success",
+	})
+	return nil
+}
+
+func UninstallWSL(ctx context.Context, Info WSLinfo) error {
+	if err := wslcli.Default.Unregister(ctx, Info.Linux_Version); err != nil {
+		emitEvent(ctx, "uninstall:failed", fmt.Sprintf("卸载 %s 发行版失败: %s", Info.Linux_Version, wslcli.Describe(err)))
+		return err
+	}
+	return nil
+}
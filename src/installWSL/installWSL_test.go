@@ -0,0 +1,175 @@
+package installWSL
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 以FakeRunner替换默认客户端
+func fakeDefault(t *testing.T) *wslcli.FakeRunner {
+	t.Helper()
+	f := wslcli.NewFakeRunner()
+	prev := wslcli.Default
+	wslcli.Default = wslcli.New(f)
+	t.Cleanup(func() { wslcli.Default = prev })
+	return f
+}
+
+func testInfo(t *testing.T) WSLinfo {
+	return WSLinfo{
+		Linux_Version: "Ubuntu",
+		Install_Path:  &WSLpath{Path: t.TempDir()},
+		Auth:          &WSLAuth{User: "bob", Password: "secret123"},
+		Local_File:    "/x/u.tar",
+	}
+}
+
+func TestInstallFlow(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("--list", "--verbose").Reply(wslcli.FakeList(wslcli.Distro{Name: "Debian", State: "Stopped", Version: 2, Default: true}))
+	f.On("--import", "...")
+	f.On("-d", "Ubuntu", "...")
+	f.On("-d", "Ubuntu", "-u", "root", "--exec", "cat", "/etc/adduser.conf").Reply(wslcli.FakeResponse{Stdout: "#NAME_REGEX=\"x\"\nNAME_REGEX=\"^[a-z][-a-z0-9_]*$\"\n"})
+	f.On("--terminate", "Ubuntu")
+
+	ctx := context.Background()
+	info := testInfo(t)
+	if err := WSL2_Check(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	if err := WSL2_Installer(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	if err := WSL2_Setting_User(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Called("--import", "Ubuntu", "*", "/x/u.tar", "--version", "2") != 1 {
+		t.Fatal("没有以WSL2导入发行版")
+	}
+	// 密码只能通过stdin传给chpasswd
+	if f.Called("-d", "Ubuntu", "-u", "root", "--exec", "chpasswd") != 1 {
+		t.Fatal("没有通过chpasswd设置密码")
+	}
+	for _, c := range f.Calls() {
+		if strings.Contains(strings.Join(c.Args, " "), "secret123") {
+			t.Fatalf("密码出现在命令行参数中: %v", c.Args)
+		}
+	}
+	if f.Called("--terminate", "Ubuntu") != 1 {
+		t.Fatal("配置完成后没有关闭发行版")
+	}
+}
+
+// 用户名不合规时在执行任何配置命令前返回
+func TestSettingUserValidation(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("-d", "Ubuntu", "...")
+
+	info := testInfo(t)
+	info.Auth = &WSLAuth{User: "bob;rm -rf /", Password: "x"}
+	err := WSL2_Setting_User(context.Background(), info)
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Field != FieldUsername {
+		t.Fatalf("err = %v, 期望用户名校验错误", err)
+	}
+	// 只读取了 adduser.conf
+	if n := len(f.Calls()); n != 1 {
+		t.Fatalf("校验失败后仍执行了 %d 条命令", n-1)
+	}
+}
+
+func TestCheckExistingDistro(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("--list", "--verbose").Reply(wslcli.FakeList(wslcli.Distro{Name: "Ubuntu", State: "Running", Version: 2}))
+	f.On("-d", "Ubuntu", "...").Reply(wslcli.FakeResponse{Stdout: "[user]\ndefault=bob\n"})
+
+	if err := WSL2_Check(context.Background(), testInfo(t)); err == nil || err.Error() != "发行版已存在" {
+		t.Fatalf("err = %v, 期望发行版已存在", err)
+	}
+}
+
+// 导入失败时错误随任务的 install:progress 推送
+func TestInstallerFailureScopedToJob(t *testing.T) {
+	events := recordEvents(t)
+	f := fakeDefault(t)
+	f.On("--import", "...").Reply(wslcli.FakeResponse{Stdout: "Error code: Wsl/Service/RegisterDistro/ERROR_ALREADY_EXISTS", ExitCode: 1, UTF16: true})
+
+	job, ctx := newJob(context.Background(), "install", "Ubuntu")
+	if err := WSL2_Installer(ctx, testInfo(t)); err == nil {
+		t.Fatal("导入失败时应返回错误")
+	}
+	last, ok := LastProgress(job.ID)
+	if !ok || last.Phase != PhaseFailed || !strings.Contains(last.Error, "解压安装出现错误") {
+		t.Fatalf("最后进度 = %+v", last)
+	}
+	// 无法确认同名发行版不存在,清理时不能注销
+	if job.Imported() {
+		t.Fatal("导入失败后不应标记为已导入")
+	}
+	if len(events.named("wsl-error")) != 0 {
+		t.Fatal("仍推送了全局 wsl-error 事件")
+	}
+}
+
+// 同名发行版已存在时导入失败,清理不能注销用户已有的发行版
+func TestCleanupKeepsExistingDistro(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("--list", "--verbose").Reply(wslcli.FakeList(wslcli.Distro{Name: "Ubuntu", State: "Stopped", Version: 2}))
+	f.On("--import", "...").Reply(wslcli.FakeResponse{Stdout: "Error code: Wsl/Service/RegisterDistro/ERROR_ALREADY_EXISTS", ExitCode: 1, UTF16: true})
+	f.On("--terminate", "Ubuntu")
+	f.On("--unregister", "Ubuntu")
+
+	job, ctx := newJob(context.Background(), "install", "Ubuntu")
+	info := testInfo(t)
+	if err := WSL2_Installer(ctx, info); err == nil {
+		t.Fatal("导入失败时应返回错误")
+	}
+	CleanupInstall(ctx, info, job)
+	if job.Imported() || f.Called("--unregister", "Ubuntu") != 0 || f.Called("--terminate", "Ubuntu") != 0 {
+		t.Fatal("清理注销了已存在的发行版")
+	}
+}
+
+// 确认未注册后开始导入,中途取消时清理注销导入了一半的发行版
+func TestCleanupAfterCancelledImport(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("--list", "--verbose").Reply(wslcli.FakeList(wslcli.Distro{Name: "Debian", State: "Stopped", Version: 2}))
+	f.On("--terminate", "Ubuntu")
+	f.On("--unregister", "Ubuntu")
+
+	job, ctx := newJob(context.Background(), "install", "Ubuntu")
+	ctx, cancel := context.WithCancel(ctx)
+	// 导入开始后取消
+	f.On("--import", "...").Handle(func(wslcli.FakeCall) wslcli.FakeResponse {
+		cancel()
+		return wslcli.FakeResponse{Delay: time.Second}
+	})
+	info := testInfo(t)
+	if err := WSL2_Installer(ctx, info); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, 期望 context.Canceled", err)
+	}
+	CleanupInstall(ctx, info, job)
+	if !job.Imported() || f.Called("--unregister", "Ubuntu") != 1 {
+		t.Fatal("取消导入后没有注销发行版")
+	}
+}
+
+func TestUninstallError(t *testing.T) {
+	recordEvents(t)
+	f := fakeDefault(t)
+	f.On("--unregister", "Ubuntu").Reply(wslcli.FakeResponse{Stdout: "Error: not found", ExitCode: 1, UTF16: true})
+	if err := UninstallWSL(context.Background(), testInfo(t)); err == nil {
+		t.Fatal("注销失败时应返回错误")
+	}
+}
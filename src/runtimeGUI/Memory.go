@@ -0,0 +1,101 @@
+package runtimeGUI
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 内存统计来源
+const (
+	MemSourceMeminfo = "meminfo"
+	MemSourceCgroup  = "cgroup"
+	MemSourceRSS     = "rss"
+)
+
+// 一个层级的内存用量,单位字节
+type MemoryStats struct {
+	// 仅虚拟机层级有总量
+	Total int64 `json:"total"`
+	Used  int64 `json:"used"`
+	// 匿名内存,即进程堆栈等
+	Anon int64 `json:"anon"`
+	// 页缓存
+	Cache  int64  `json:"cache"`
+	Source string `json:"source"`
+}
+
+// 所有WSL2发行版共用一个虚拟机,/proc/meminfo 是整个虚拟机的数据;
+// 发行版自身的用量优先取 cgroup v2 的 memory.current(根cgroup没有该文件),
+// 否则累加本发行版PID命名空间内所有进程的RSS,statm第3列为文件映射部分
+const memScript = `grep -E '^(MemTotal|MemAvailable|Buffers|Cached|AnonPages):' /proc/meminfo; ` +
+	`if [ -r /sys/fs/cgroup/memory.current ]; then ` +
+	`echo "cgroup.current $(cat /sys/fs/cgroup/memory.current)"; grep -E '^(anon|file) ' /sys/fs/cgroup/memory.stat | sed 's/^/cgroup./'; ` +
+	`else cat /proc/[0-9]*/statm 2>/dev/null | awk -v p="$(getconf PAGESIZE 2>/dev/null || echo 4096)" '{r+=$2; f+=$3} END {print "rss.total", r*p, f*p}'; fi`
+
+// memScript 输出中的各项
+type memSample struct {
+	memTotal, memAvailable int64
+	buffers, cached, anon  int64
+
+	source                  string
+	current, cgAnon, cgFile int64
+	rss, rssFile            int64
+}
+
+// 解析一行,不属于内存统计时返回false
+func (m *memSample) parseLine(fields []string) bool {
+	if len(fields) < 2 {
+		return false
+	}
+	n, _ := strconv.ParseInt(fields[1], 10, 64)
+	switch fields[0] {
+	case "MemTotal:":
+		m.memTotal = n * 1024
+	case "MemAvailable:":
+		m.memAvailable = n * 1024
+	case "Buffers:":
+		m.buffers = n * 1024
+	case "Cached:":
+		m.cached = n * 1024
+	case "AnonPages:":
+		m.anon = n * 1024
+	case "cgroup.current":
+		m.source, m.current = MemSourceCgroup, n
+	case "cgroup.anon":
+		m.cgAnon = n
+	case "cgroup.file":
+		m.cgFile = n
+	case "rss.total":
+		m.source, m.rss = MemSourceRSS, n
+		if len(fields) > 2 {
+			m.rssFile, _ = strconv.ParseInt(fields[2], 10, 64)
+		}
+	default:
+		return strings.HasPrefix(fields[0], "cgroup.")
+	}
+	return true
+}
+
+// 虚拟机层级
+func (m memSample) vm() MemoryStats {
+	return MemoryStats{
+		Total:  m.memTotal,
+		Used:   m.memTotal - m.memAvailable,
+		Anon:   m.anon,
+		Cache:  m.buffers + m.cached,
+		Source: MemSourceMeminfo,
+	}
+}
+
+// 发行版层级,两种来源都没有时退回虚拟机数据
+func (m memSample) distro() MemoryStats {
+	switch m.source {
+	case MemSourceCgroup:
+		return MemoryStats{Used: m.current, Anon: m.cgAnon, Cache: m.cgFile, Source: MemSourceCgroup}
+	case MemSourceRSS:
+		return MemoryStats{Used: m.rss, Anon: m.rss - m.rssFile, Cache: m.rssFile, Source: MemSourceRSS}
+	}
+	vm := m.vm()
+	vm.Total = 0
+	return vm
+}
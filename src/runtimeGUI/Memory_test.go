@@ -0,0 +1,34 @@
+package runtimeGUI
+
+import (
+	"strings"
+	"testing"
+)
+
+func parseMemSample(out string) memSample {
+	var m memSample
+	for _, l := range strings.Split(out, "\n") {
+		m.parseLine(strings.Fields(l))
+	}
+	return m
+}
+
+// 有 cgroup 时按 cgroup 统计发行版内存,虚拟机内存来自 meminfo
+func TestMemSampleCgroup(t *testing.T) {
+	m := parseMemSample("MemTotal: 8000 kB\nMemAvailable: 6000 kB\nBuffers: 100 kB\nCached: 900 kB\nAnonPages: 700 kB\ncgroup.current 4096\ncgroup.anon 1000\ncgroup.file 3000\n")
+	vm, d := m.vm(), m.distro()
+	if vm.Total != 8000*1024 || vm.Used != 2000*1024 || vm.Cache != 1000*1024 || vm.Anon != 700*1024 {
+		t.Fatalf("vm = %+v", vm)
+	}
+	if d.Used != 4096 || d.Anon != 1000 || d.Cache != 3000 || d.Source != MemSourceCgroup {
+		t.Fatalf("distro = %+v", d)
+	}
+}
+
+// 没有 cgroup 时退回进程RSS合计
+func TestMemSampleRSS(t *testing.T) {
+	m := parseMemSample("MemTotal: 8000 kB\nMemAvailable: 6000 kB\nrss.total 5000 2000\n")
+	if d := m.distro(); d.Used != 5000 || d.Anon != 3000 || d.Source != MemSourceRSS {
+		t.Fatalf("distro = %+v", d)
+	}
+}
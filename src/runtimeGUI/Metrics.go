@@ -0,0 +1,307 @@
+package runtimeGUI
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 采样间隔的范围与默认值
+const (
+	DefaultMetricsInterval = 2 * time.Second
+	minMetricsInterval     = time.Second
+	maxMetricsInterval     = time.Minute
+)
+
+// 每个间隔输出一帧,以 -- 结尾,间隔秒数由 $1 传入
+const metricsScript = `while :; do head -n 1 /proc/stat; ` + memScript + `; cat /proc/loadavg; tail -n +3 /proc/net/dev; df -kP / | tail -n 1; echo --; sleep "$1" || exit; done`
+
+// 推送给前端的一次采样,字段与 Metrics 相同并附带原始数值
+type MetricsUpdate struct {
+	Distro string `json:"distro"`
+	// Unix毫秒
+	Time int64 `json:"time"`
+	Metrics
+	CPUPercent float64 `json:"cpuPercent"`
+	// 发行版用量与虚拟机总量
+	MemUsedBytes  int64      `json:"memUsedBytes"`
+	MemTotalBytes int64      `json:"memTotalBytes"`
+	Load          [3]float64 `json:"load"`
+	// 每秒收发字节数,不含 lo
+	NetRxRate float64 `json:"netRxRate"`
+	NetTxRate float64 `json:"netTxRate"`
+	// 发行版根文件系统
+	FsUsedBytes  int64 `json:"fsUsedBytes"`
+	FsTotalBytes int64 `json:"fsTotalBytes"`
+}
+
+// 写入历史的数值
+func (m MetricsUpdate) point() MetricsPoint {
+	return MetricsPoint{
+		Time: m.Time, CPU: m.CPUPercent, MemUsed: m.MemUsedBytes, MemTotal: m.MemTotalBytes,
+		DiskUsed: m.FsUsedBytes, DiskTotal: m.FsTotalBytes, NetRx: m.NetRxRate, NetTx: m.NetTxRate,
+		Load1: m.Load[0], Load5: m.Load[1], Load15: m.Load[2],
+	}
+}
+
+// 为订阅的运行中发行版各保持一个采样进程,按间隔推送 MetricsUpdate
+type MetricsCollector struct {
+	// 每次采样后调用
+	OnUpdate func(MetricsUpdate)
+
+	mu       sync.Mutex
+	interval time.Duration
+	subs     map[string]bool
+	agents   map[string]*metricsAgent
+	// 停止采样后仍保留,直到程序退出
+	history map[string]*metricsHistory
+}
+
+type metricsAgent struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var DefaultMetrics = NewMetricsCollector()
+
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{
+		interval: DefaultMetricsInterval,
+		subs:     map[string]bool{},
+		agents:   map[string]*metricsAgent{},
+		history:  map[string]*metricsHistory{},
+	}
+}
+
+// 当前采样间隔
+func (c *MetricsCollector) Interval() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.interval
+}
+
+// 设置采样间隔并重启采样进程,返回实际生效的值
+func (c *MetricsCollector) SetInterval(ctx context.Context, d time.Duration) time.Duration {
+	d = min(max(d, minMetricsInterval), maxMetricsInterval).Round(time.Second)
+	c.mu.Lock()
+	changed := d != c.interval
+	c.interval = d
+	var names []string
+	if changed {
+		for name := range c.agents {
+			names = append(names, name)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, name := range names {
+		c.stopAgent(name)
+		c.mu.Lock()
+		if c.subs[name] {
+			c.startAgentLocked(ctx, name)
+		}
+		c.mu.Unlock()
+	}
+	return d
+}
+
+// 订阅发行版的指标,发行版未运行时只记录订阅,不会唤醒发行版
+// 重复订阅时若采样进程已退出则重新启动
+func (c *MetricsCollector) Subscribe(ctx context.Context, name string) error {
+	c.mu.Lock()
+	c.subs[name] = true
+	_, running := c.agents[name]
+	c.mu.Unlock()
+	if running {
+		return nil
+	}
+
+	distros, err := wslcli.Default.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range distros {
+		if d.Name != name || d.State != wslcli.StateRunning {
+			continue
+		}
+		c.mu.Lock()
+		if c.subs[name] && c.agents[name] == nil {
+			c.startAgentLocked(ctx, name)
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// 取消订阅并结束采样进程
+func (c *MetricsCollector) Unsubscribe(name string) {
+	c.mu.Lock()
+	delete(c.subs, name)
+	c.mu.Unlock()
+	c.stopAgent(name)
+}
+
+// 取消所有订阅,程序退出时调用
+func (c *MetricsCollector) Stop() {
+	c.mu.Lock()
+	var names []string
+	for name := range c.agents {
+		names = append(names, name)
+	}
+	c.subs = map[string]bool{}
+	c.mu.Unlock()
+	for _, name := range names {
+		c.stopAgent(name)
+	}
+}
+
+func (c *MetricsCollector) stopAgent(name string) {
+	c.mu.Lock()
+	agent := c.agents[name]
+	c.mu.Unlock()
+	if agent == nil {
+		return
+	}
+	agent.cancel()
+	<-agent.done
+}
+
+// 启动采样进程,进程退出(如发行版被关闭)后移除,等待下次订阅
+func (c *MetricsCollector) startAgentLocked(ctx context.Context, name string) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	agent := &metricsAgent{cancel: cancel, done: make(chan struct{})}
+	c.agents[name] = agent
+	interval := c.interval
+
+	pr, pw := io.Pipe()
+	go func() {
+		args := []string{"sh", "-c", metricsScript, "sh", strconv.Itoa(int(interval / time.Second))}
+		err := wslcli.Default.Stream(ctx, name, wslcli.ExecOptions{ReadOnly: true}, pw, args...)
+		pw.CloseWithError(err)
+	}()
+	go func() {
+		defer close(agent.done)
+		defer func() {
+			c.mu.Lock()
+			if c.agents[name] == agent {
+				delete(c.agents, name)
+			}
+			c.mu.Unlock()
+			cancel()
+			pr.Close()
+		}()
+		c.readFrames(name, pr)
+	}()
+}
+
+// 逐帧解析采样输出,CPU占用率由相邻两帧的差值计算
+func (c *MetricsCollector) readFrames(name string, r io.Reader) {
+	var prev, cur metricsFrame
+	hasPrev := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "--" {
+			cur.parseLine(line)
+			continue
+		}
+		cur.at = time.Now()
+		if hasPrev {
+			m := cur.update(name, prev)
+			c.record(name, m.point())
+			if c.OnUpdate != nil {
+				c.OnUpdate(m)
+			}
+		}
+		prev, cur, hasPrev = cur, metricsFrame{}, true
+	}
+}
+
+// 一帧原始数据
+type metricsFrame struct {
+	at          time.Time
+	idle, total uint64
+	mem         memSample
+	load        [3]float64
+	rx, tx      uint64
+	fsUsed      int64
+	fsTotal     int64
+}
+
+func (f *metricsFrame) parseLine(line string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+	switch fields[0] {
+	case "cpu":
+		for i := 1; i < len(fields); i++ {
+			val, _ := strconv.ParseUint(fields[i], 10, 64)
+			f.total += val
+			if i == 4 {
+				f.idle = val
+			}
+		}
+	default:
+		switch {
+		case f.mem.parseLine(fields):
+		// /proc/loadavg: 0.00 0.01 0.05 1/123 456
+		case len(fields) == 5 && strings.Contains(fields[3], "/"):
+			for i := range f.load {
+				f.load[i], _ = strconv.ParseFloat(fields[i], 64)
+			}
+		// df -kP: 文件系统 总KB 已用KB 可用KB 百分比 挂载点
+		case len(fields) == 6 && fields[5] == "/":
+			total, _ := strconv.ParseInt(fields[1], 10, 64)
+			used, _ := strconv.ParseInt(fields[2], 10, 64)
+			f.fsTotal, f.fsUsed = total*1024, used*1024
+		// /proc/net/dev: 网卡: 接收字节 ... 第9列为发送字节,计数可能与冒号相连
+		case strings.Contains(line, ":"):
+			iface, rest, _ := strings.Cut(line, ":")
+			counters := strings.Fields(rest)
+			if strings.TrimSpace(iface) == "lo" || len(counters) < 9 {
+				return
+			}
+			rx, _ := strconv.ParseUint(counters[0], 10, 64)
+			tx, _ := strconv.ParseUint(counters[8], 10, 64)
+			f.rx += rx
+			f.tx += tx
+		}
+	}
+}
+
+// 计数器每秒的增量,计数器重置时为0
+func rate(cur, prev uint64, elapsed time.Duration) float64 {
+	if cur < prev || elapsed <= 0 {
+		return 0
+	}
+	return float64(cur-prev) / elapsed.Seconds()
+}
+
+func (f metricsFrame) update(name string, prev metricsFrame) MetricsUpdate {
+	var cpu float64
+	if f.total > prev.total && f.idle >= prev.idle {
+		total := f.total - prev.total
+		cpu = float64(total-min(f.idle-prev.idle, total)) / float64(total) * 100
+	}
+	vm, distro := f.mem.vm(), f.mem.distro()
+	return MetricsUpdate{
+		Distro:        name,
+		Time:          time.Now().UnixMilli(),
+		Metrics:       newMetrics(cpu, vm, distro, getFileSize(name)),
+		CPUPercent:    cpu,
+		MemUsedBytes:  distro.Used,
+		MemTotalBytes: vm.Total,
+		Load:          f.load,
+		NetRxRate:     rate(f.rx, prev.rx, f.at.Sub(prev.at)),
+		NetTxRate:     rate(f.tx, prev.tx, f.at.Sub(prev.at)),
+		FsUsedBytes:   f.fsUsed,
+		FsTotalBytes:  f.fsTotal,
+	}
+}
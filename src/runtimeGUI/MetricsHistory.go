@@ -0,0 +1,254 @@
+package runtimeGUI
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 历史指标的时间范围
+type MetricsRange string
+
+const (
+	Range5m  MetricsRange = "5m"
+	Range1h  MetricsRange = "1h"
+	Range24h MetricsRange = "24h"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllMetricsRanges = []struct {
+	Value  MetricsRange
+	TSName string
+}{
+	{Range5m, "FiveMinutes"},
+	{Range1h, "OneHour"},
+	{Range24h, "OneDay"},
+}
+
+// 各范围的聚合粒度与保留点数,5m 保留原始采样
+var historyTiers = []struct {
+	rng    MetricsRange
+	span   time.Duration
+	bucket time.Duration
+	size   int
+}{
+	{Range5m, 5 * time.Minute, 0, 300},
+	{Range1h, time.Hour, 10 * time.Second, 360},
+	{Range24h, 24 * time.Hour, 4 * time.Minute, 360},
+}
+
+// 历史中的一个点,聚合后的点为区间内的平均值
+type MetricsPoint struct {
+	// Unix毫秒,聚合点为区间起点
+	Time int64 `json:"time"`
+	// CPU占用百分比
+	CPU      float64 `json:"cpu"`
+	MemUsed  int64   `json:"memUsed"`
+	MemTotal int64   `json:"memTotal"`
+	// 发行版根文件系统
+	DiskUsed  int64 `json:"diskUsed"`
+	DiskTotal int64 `json:"diskTotal"`
+	// 每秒收发字节数,不含 lo
+	NetRx  float64 `json:"netRx"`
+	NetTx  float64 `json:"netTx"`
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+}
+
+// 固定容量的环形缓冲,写满后覆盖最旧的数据
+type ring[T any] struct {
+	buf  []T
+	next int
+	full bool
+}
+
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{buf: make([]T, size)}
+}
+
+func (r *ring[T]) push(v T) {
+	r.buf[r.next] = v
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// 从旧到新
+func (r *ring[T]) items() []T {
+	if !r.full {
+		return append([]T(nil), r.buf[:r.next]...)
+	}
+	return append(append([]T(nil), r.buf[r.next:]...), r.buf[:r.next]...)
+}
+
+// 一个范围的缓冲与正在累计的区间
+type historyTier struct {
+	bucket time.Duration
+	ring   *ring[MetricsPoint]
+	acc    pointSum
+}
+
+// MetricsPoint 中参与平均的字段数
+const pointFields = 10
+
+// 区间内各项的累加值
+type pointSum struct {
+	start int64
+	n     int
+	sum   [pointFields]float64
+}
+
+func (s *pointSum) add(p MetricsPoint) {
+	for i, v := range p.values() {
+		s.sum[i] += v
+	}
+	s.n++
+}
+
+func (s *pointSum) mean() MetricsPoint {
+	var v [pointFields]float64
+	for i := range v {
+		v[i] = s.sum[i] / float64(s.n)
+	}
+	return MetricsPoint{
+		Time: s.start, CPU: v[0], MemUsed: int64(v[1]), MemTotal: int64(v[2]),
+		DiskUsed: int64(v[3]), DiskTotal: int64(v[4]), NetRx: v[5], NetTx: v[6],
+		Load1: v[7], Load5: v[8], Load15: v[9],
+	}
+}
+
+func (p MetricsPoint) values() [pointFields]float64 {
+	return [pointFields]float64{p.CPU, float64(p.MemUsed), float64(p.MemTotal), float64(p.DiskUsed), float64(p.DiskTotal), p.NetRx, p.NetTx, p.Load1, p.Load5, p.Load15}
+}
+
+// 单个发行版的历史
+type metricsHistory struct {
+	mu    sync.Mutex
+	tiers map[MetricsRange]*historyTier
+}
+
+func newMetricsHistory() *metricsHistory {
+	h := &metricsHistory{tiers: map[MetricsRange]*historyTier{}}
+	for _, t := range historyTiers {
+		h.tiers[t.rng] = &historyTier{bucket: t.bucket, ring: newRing[MetricsPoint](t.size)}
+	}
+	return h
+}
+
+func (h *metricsHistory) add(p MetricsPoint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, t := range h.tiers {
+		if t.bucket == 0 {
+			t.ring.push(p)
+			continue
+		}
+		start := time.UnixMilli(p.Time).Truncate(t.bucket).UnixMilli()
+		if t.acc.n > 0 && t.acc.start != start {
+			t.ring.push(t.acc.mean())
+			t.acc = pointSum{}
+		}
+		t.acc.start = start
+		t.acc.add(p)
+	}
+}
+
+// 范围内的点,包含尚未结束的区间
+func (h *metricsHistory) query(rng MetricsRange, now time.Time) []MetricsPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var span time.Duration
+	for _, t := range historyTiers {
+		if t.rng == rng {
+			span = t.span
+		}
+	}
+	t := h.tiers[rng]
+	points := t.ring.items()
+	if t.acc.n > 0 {
+		points = append(points, t.acc.mean())
+	}
+	since := now.Add(-span).UnixMilli()
+	out := []MetricsPoint{}
+	for _, p := range points {
+		if p.Time >= since {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func validRange(rng MetricsRange) error {
+	for _, t := range historyTiers {
+		if t.rng == rng {
+			return nil
+		}
+	}
+	return fmt.Errorf("未知的时间范围 %q", rng)
+}
+
+// 发行版在范围内的历史指标,从未采样过时返回空
+func (c *MetricsCollector) History(name string, rng MetricsRange) ([]MetricsPoint, error) {
+	if err := validRange(rng); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	h := c.history[name]
+	c.mu.Unlock()
+	if h == nil {
+		return []MetricsPoint{}, nil
+	}
+	return h.query(rng, time.Now()), nil
+}
+
+func (c *MetricsCollector) record(name string, p MetricsPoint) {
+	c.mu.Lock()
+	h := c.history[name]
+	if h == nil {
+		h = newMetricsHistory()
+		c.history[name] = h
+	}
+	c.mu.Unlock()
+	h.add(p)
+}
+
+// 导出历史指标,扩展名为 .csv 时写CSV,否则写JSON数组
+func (c *MetricsCollector) ExportHistory(path, name string, rng MetricsRange) error {
+	points, err := c.History(name, rng)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		w := csv.NewWriter(file)
+		w.Write([]string{"time", "cpu", "memUsed", "memTotal", "diskUsed", "diskTotal", "netRx", "netTx", "load1", "load5", "load15"})
+		f := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+		i := func(v int64) string { return strconv.FormatInt(v, 10) }
+		for _, p := range points {
+			w.Write([]string{
+				time.UnixMilli(p.Time).Format(time.RFC3339),
+				f(p.CPU), i(p.MemUsed), i(p.MemTotal), i(p.DiskUsed), i(p.DiskTotal),
+				f(p.NetRx), f(p.NetTx), f(p.Load1), f(p.Load5), f(p.Load15),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	return enc.Encode(points)
+}
@@ -0,0 +1,98 @@
+package runtimeGUI
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRing(t *testing.T) {
+	r := newRing[int](3)
+	for i := 1; i <= 5; i++ {
+		r.push(i)
+	}
+	got := r.items()
+	if len(got) != 3 || got[0] != 3 || got[2] != 5 {
+		t.Fatalf("items = %v, 期望按时间顺序保留最新的3个", got)
+	}
+}
+
+// 两小时的逐秒采样,各范围按粒度聚合
+func TestMetricsHistoryQuery(t *testing.T) {
+	h := newMetricsHistory()
+	now := time.Now().Truncate(time.Hour)
+	base := now.Add(-2 * time.Hour)
+	for i := 0; i < 7200; i++ {
+		h.add(MetricsPoint{Time: base.Add(time.Duration(i) * time.Second).UnixMilli(), CPU: 50})
+	}
+	for _, c := range []struct {
+		rng  MetricsRange
+		span time.Duration
+		max  int
+	}{
+		{Range5m, 5 * time.Minute, 300},
+		{Range1h, time.Hour, 360},
+		{Range24h, 24 * time.Hour, 360},
+	} {
+		pts := h.query(c.rng, now)
+		if len(pts) == 0 || len(pts) > c.max {
+			t.Fatalf("%s: %d 个点", c.rng, len(pts))
+		}
+		if pts[0].Time < now.Add(-c.span).UnixMilli() {
+			t.Fatalf("%s: 包含范围外的点", c.rng)
+		}
+		for _, p := range pts {
+			if p.CPU != 50 {
+				t.Fatalf("%s: 聚合后的CPU = %v", c.rng, p.CPU)
+			}
+		}
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	c := NewMetricsCollector()
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		c.record("Ubuntu", MetricsPoint{Time: now.Add(time.Duration(i-10) * time.Second).UnixMilli(), CPU: 12.5, MemUsed: 1024})
+	}
+	if _, err := c.History("Ubuntu", "7d"); err == nil {
+		t.Fatal("未知范围没有返回错误")
+	}
+	if pts, err := c.History("Debian", Range5m); err != nil || len(pts) != 0 {
+		t.Fatalf("未采样的发行版 = %v, %v", pts, err)
+	}
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "metrics.csv")
+	if err := c.ExportHistory(csvPath, "Ubuntu", Range5m); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 11 || rows[0][0] != "time" || rows[1][1] != "12.50" || rows[1][2] != "1024" {
+		t.Fatalf("csv = %v", rows[:2])
+	}
+
+	jsonPath := filepath.Join(dir, "metrics.json")
+	if err := c.ExportHistory(jsonPath, "Ubuntu", Range5m); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pts []MetricsPoint
+	if err := json.Unmarshal(data, &pts); err != nil || len(pts) != 10 {
+		t.Fatalf("json = %d 个点, %v", len(pts), err)
+	}
+}
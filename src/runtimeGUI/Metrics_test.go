@@ -0,0 +1,116 @@
+package runtimeGUI
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 以FakeRunner替换默认客户端
+func fakeDefault(t *testing.T) *wslcli.FakeRunner {
+	t.Helper()
+	f := wslcli.NewFakeRunner()
+	prev := wslcli.Default
+	wslcli.Default = wslcli.New(f)
+	t.Cleanup(func() { wslcli.Default = prev })
+	return f
+}
+
+// 采样脚本输出的一帧
+func metricsFrameText(busy, idle int) string {
+	return fmt.Sprintf("cpu  %d 0 0 %d 0 0 0\nMemTotal:  8000000 kB\nMemAvailable: 6000000 kB\n0.10 0.20 0.30 1/100 42\n--\n", busy, idle)
+}
+
+func (c *MetricsCollector) agentCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.agents)
+}
+
+// 等待条件成立,超时返回false
+func eventually(cond func() bool) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestMetricsSubscribe(t *testing.T) {
+	f := fakeDefault(t)
+	f.On("--list", "--verbose").Reply(wslcli.FakeList(
+		wslcli.Distro{Name: "Ubuntu", State: "Running", Version: 2},
+		wslcli.Distro{Name: "Debian", State: "Stopped", Version: 2},
+	))
+	f.On("-d", "Ubuntu", "--exec", "sh", "-c", metricsScript, "sh", "...").Reply(wslcli.FakeResponse{Stdout: metricsFrameText(100, 900) + metricsFrameText(150, 1000), Hold: true})
+
+	c := NewMetricsCollector()
+	defer c.Stop()
+	got := make(chan MetricsUpdate, 10)
+	c.OnUpdate = func(m MetricsUpdate) { got <- m }
+	ctx := context.Background()
+	if err := c.Subscribe(ctx, "Debian"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Subscribe(ctx, "Ubuntu"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 第二帧与第一帧的差值: 50 忙 / 150 总
+	select {
+	case m := <-got:
+		if m.CPU != "33.3%" || m.MemUsedBytes != 2000000*1024 || m.Load[2] != 0.3 {
+			t.Fatalf("update = %+v", m)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("没有收到指标")
+	}
+	if f.Called("-d", "Debian", "...") != 0 {
+		t.Fatal("订阅唤醒了已停止的发行版")
+	}
+
+	// 重复订阅复用同一个采样进程
+	c.Subscribe(ctx, "Ubuntu")
+	if f.Called("-d", "Ubuntu", "...") != 1 || c.agentCount() != 1 {
+		t.Fatal("重复订阅启动了新的采样进程")
+	}
+
+	// 修改间隔后以新间隔重启采样进程
+	if d := c.SetInterval(ctx, 5*time.Second); d != 5*time.Second {
+		t.Fatalf("interval = %s", d)
+	}
+	if !eventually(func() bool { return f.Called("-d", "Ubuntu", "--exec", "sh", "-c", metricsScript, "sh", "5") == 1 }) {
+		t.Fatalf("没有以新间隔重启: %v", f.Calls())
+	}
+
+	c.Unsubscribe("Ubuntu")
+	if c.agentCount() != 0 {
+		t.Fatal("取消订阅后仍有采样进程")
+	}
+}
+
+func TestMetricsFrameParse(t *testing.T) {
+	var f metricsFrame
+	for _, l := range []string{
+		"  eth0: 1000 10 0 0 0 0 0 0 2000 20 0 0 0 0 0 0",
+		"    lo:99999 1 0 0 0 0 0 0 99999 1 0 0 0 0 0 0",
+		"Filesystem 1024-blocks Used Available Capacity Mounted on",
+		"/dev/sdc 1000 400 600 40% /",
+		"0.10 0.20 0.30 1/100 42",
+	} {
+		f.parseLine(l)
+	}
+	// lo 不计入网络流量
+	if f.rx != 1000 || f.tx != 2000 {
+		t.Fatalf("rx/tx = %d/%d", f.rx, f.tx)
+	}
+	if f.fsUsed != 400*1024 || f.load[0] != 0.1 {
+		t.Fatalf("frame = %+v", f)
+	}
+}
@@ -0,0 +1,286 @@
+package runtimeGUI
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	setting "Golang-WSL-GUI/src/Setting"
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 发行版的网卡
+type NetInterface struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	MAC   string `json:"mac"`
+	MTU   int    `json:"mtu"`
+	// CIDR形式,如 172.20.1.2/20
+	Addresses []string `json:"addresses"`
+}
+
+// 监听中的套接字
+type ListenSocket struct {
+	// tcp、tcp6、udp 或 udp6
+	Protocol string `json:"protocol"`
+	Address  string `json:"address"`
+	Port     int    `json:"port"`
+	// 持有该套接字的进程,无法确定时为0
+	PID     int    `json:"pid"`
+	Process string `json:"process"`
+	// 能否从Windows访问,不能时Reason说明原因
+	Reachable bool   `json:"reachable"`
+	Reason    string `json:"reason"`
+	// Windows 中使用的地址,如 localhost:3000
+	WindowsAddress string `json:"windowsAddress"`
+}
+
+// 发行版网络状态与 .wslconfig 的对照
+type NetworkReport struct {
+	NetworkMode         string         `json:"networkMode"`
+	LocalhostForwarding bool           `json:"localhostForwarding"`
+	IgnoredPorts        []int          `json:"ignoredPorts"`
+	Interfaces          []NetInterface `json:"interfaces"`
+	Sockets             []ListenSocket `json:"sockets"`
+}
+
+// 网卡信息取自 /sys/class/net 与 ip 命令,套接字取自 /proc/net/*,
+// 再由 /proc/<pid>/fd 中的 socket:[inode] 找到所属进程,需以root执行
+const networkScript = `for d in /sys/class/net/*; do n=${d##*/}; ` +
+	`echo "if $n $(cat "$d/operstate" 2>/dev/null) $(cat "$d/mtu" 2>/dev/null) $(cat "$d/address" 2>/dev/null)"; done; ` +
+	`ip -o addr show 2>/dev/null | sed 's/^/addr /'; ` +
+	`for t in tcp tcp6 udp udp6; do [ -r /proc/net/$t ] && tail -n +2 /proc/net/$t | sed "s/^/$t /"; done; ` +
+	`for p in /proc/[0-9]*; do { read -r c < "$p/comm"; } 2>/dev/null && echo "comm ${p#/proc/} $c"; done; ` +
+	`ls -l /proc/[0-9]*/fd 2>/dev/null | sed -n 's/^\(\/proc\/[0-9]*\)\/fd:$/fd \1/p; s/.*socket:\[\([0-9]*\)\].*/sock \1/p'`
+
+// /proc/net/tcp 中的 LISTEN 状态
+const tcpListen = "0A"
+
+// 列出发行版的网卡与监听端口,并按 .wslconfig 判断各端口能否从Windows访问
+func InspectNetwork(ctx context.Context, name string) (*NetworkReport, error) {
+	res, err := wslcli.Default.Exec(ctx, name, wslcli.ExecOptions{User: "root", Timeout: metricsTimeout, ReadOnly: true}, "sh", "-c", networkScript)
+	if err != nil {
+		return nil, err
+	}
+	// 按WSL实际生效的设置判断,不能用设置页的推荐默认值
+	cfg, err := setting.ReadWslNetworkConfig()
+	if err != nil {
+		return nil, fmt.Errorf("读取 .wslconfig 失败: %w", err)
+	}
+	report := parseNetwork(res.Text())
+	applyWslConfig(report, cfg)
+	return report, nil
+}
+
+func parseNetwork(text string) *NetworkReport {
+	report := &NetworkReport{Interfaces: []NetInterface{}, Sockets: []ListenSocket{}}
+	ifaces := map[string]*NetInterface{}
+	comms := map[int]string{}
+	owners := map[string]int{}
+	// 与 report.Sockets 一一对应
+	var inodes []string
+	pid := 0
+
+	for _, line := range strings.Split(text, "\n") {
+		tag, rest, _ := strings.Cut(strings.TrimRight(line, "\r"), " ")
+		fields := strings.Fields(rest)
+		switch tag {
+		case "if":
+			if len(fields) == 0 {
+				continue
+			}
+			iface := &NetInterface{Name: fields[0], Addresses: []string{}}
+			if len(fields) > 1 {
+				iface.State = fields[1]
+			}
+			if len(fields) > 2 {
+				iface.MTU, _ = strconv.Atoi(fields[2])
+			}
+			if len(fields) > 3 {
+				iface.MAC = fields[3]
+			}
+			ifaces[iface.Name] = iface
+		case "addr":
+			// 2: eth0    inet 172.20.1.2/20 brd 172.20.15.255 scope global eth0
+			if len(fields) < 4 || fields[2] != "inet" && fields[2] != "inet6" {
+				continue
+			}
+			name, _, _ := strings.Cut(fields[1], "@")
+			if iface := ifaces[name]; iface != nil {
+				iface.Addresses = append(iface.Addresses, fields[3])
+			}
+		case "tcp", "tcp6", "udp", "udp6":
+			// sl local_address rem_address st ... uid timeout inode
+			if len(fields) < 10 {
+				continue
+			}
+			if strings.HasPrefix(tag, "tcp") && fields[3] != tcpListen {
+				continue
+			}
+			// 未连接的UDP套接字远端为0
+			if strings.HasPrefix(tag, "udp") && strings.Trim(fields[2], "0:") != "" {
+				continue
+			}
+			addr, port, ok := parseSockAddr(fields[1])
+			if !ok {
+				continue
+			}
+			report.Sockets = append(report.Sockets, ListenSocket{Protocol: tag, Address: addr, Port: port})
+			inodes = append(inodes, fields[9])
+		case "comm":
+			if len(fields) > 1 {
+				n, _ := strconv.Atoi(fields[0])
+				comms[n] = strings.Join(fields[1:], " ")
+			}
+		case "fd":
+			pid, _ = strconv.Atoi(strings.TrimPrefix(rest, "/proc/"))
+		case "sock":
+			// 多个进程共享同一套接字时取最先出现的
+			if _, ok := owners[rest]; !ok && pid > 0 {
+				owners[rest] = pid
+			}
+		}
+	}
+
+	for _, iface := range ifaces {
+		report.Interfaces = append(report.Interfaces, *iface)
+	}
+	sort.Slice(report.Interfaces, func(i, j int) bool { return report.Interfaces[i].Name < report.Interfaces[j].Name })
+
+	sockets := report.Sockets
+	for i := range sockets {
+		if p, ok := owners[inodes[i]]; ok {
+			sockets[i].PID, sockets[i].Process = p, comms[p]
+		}
+	}
+	sort.SliceStable(sockets, func(i, j int) bool {
+		if sockets[i].Port != sockets[j].Port {
+			return sockets[i].Port < sockets[j].Port
+		}
+		return sockets[i].Protocol < sockets[j].Protocol
+	})
+	return report
+}
+
+// 解析 /proc/net 中的地址,如 0100007F:1F90,地址按32位小端存储
+func parseSockAddr(s string) (string, int, bool) {
+	hexIP, hexPort, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", 0, false
+	}
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, false
+	}
+	raw, err := hex.DecodeString(hexIP)
+	if err != nil || len(raw) != net.IPv4len && len(raw) != net.IPv6len {
+		return "", 0, false
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	return ip.String(), int(port), true
+}
+
+// 解析 ignoredPorts,逗号分隔
+func parseIgnoredPorts(value string) []int {
+	ports := []int{}
+	for _, p := range strings.Split(value, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(p)); err == nil && n > 0 && n <= 65535 {
+			ports = append(ports, n)
+		}
+	}
+	return ports
+}
+
+// 虚拟机在NAT模式下的地址: 优先 eth0 的IPv4地址
+func vmAddress(ifaces []NetInterface) string {
+	fallback := ""
+	for _, iface := range ifaces {
+		for _, cidr := range iface.Addresses {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.To4() == nil {
+				continue
+			}
+			if iface.Name == "eth0" {
+				return ip.String()
+			}
+			if fallback == "" {
+				fallback = ip.String()
+			}
+		}
+	}
+	return fallback
+}
+
+// 按网络模式判断各端口能否从Windows访问
+func applyWslConfig(report *NetworkReport, cfg setting.WslNetworkConfig) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.NetworkingMode))
+	if mode == "" {
+		mode = "nat"
+	}
+	report.NetworkMode = mode
+	report.LocalhostForwarding = cfg.LocalhostForwarding
+	report.IgnoredPorts = parseIgnoredPorts(cfg.IgnoredPorts)
+	vmIP := vmAddress(report.Interfaces)
+	for i := range report.Sockets {
+		reachability(&report.Sockets[i], mode, cfg.LocalhostForwarding, report.IgnoredPorts, vmIP)
+	}
+}
+
+func reachability(s *ListenSocket, mode string, forwarding bool, ignored []int, vmIP string) {
+	ip := net.ParseIP(s.Address)
+	loopback := ip != nil && ip.IsLoopback()
+	wildcard := ip != nil && ip.IsUnspecified()
+	tcp := strings.HasPrefix(s.Protocol, "tcp")
+	localhost := fmt.Sprintf("localhost:%d", s.Port)
+	direct := net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+	viaVM := ""
+	if vmIP != "" {
+		viaVM = net.JoinHostPort(vmIP, strconv.Itoa(s.Port))
+	}
+	reachable := func(addr string) { s.Reachable, s.WindowsAddress = true, addr }
+
+	switch mode {
+	case "none":
+		s.Reason = "networkingMode=none,虚拟机没有网络"
+	case "mirrored":
+		// 镜像模式下Windows与Linux共享网卡,localhostForwarding 不生效
+		for _, p := range ignored {
+			if p == s.Port {
+				s.Reason = "端口在 ignoredPorts 中,不会镜像到 Windows"
+				return
+			}
+		}
+		if loopback || wildcard {
+			reachable(localhost)
+		} else {
+			reachable(direct)
+		}
+	default:
+		// NAT模式下 localhost 转发只支持TCP,其余需通过虚拟机地址访问
+		switch {
+		case loopback && !tcp:
+			s.Reason = "仅监听回环地址,NAT 模式下 localhost 转发不支持 UDP"
+		case loopback && !forwarding:
+			s.Reason = "仅监听回环地址,且 localhostForwarding 已关闭"
+		case (loopback || wildcard) && tcp && forwarding:
+			reachable(localhost)
+		case wildcard && viaVM != "":
+			reachable(viaVM)
+			s.Reason = "需通过虚拟机地址访问"
+		case wildcard:
+			s.Reason = "未找到虚拟机地址"
+		case vmIP != "" && s.Address == vmIP:
+			reachable(direct)
+			s.Reason = "仅监听虚拟机地址,需通过该地址访问"
+		default:
+			s.Reason = "仅监听虚拟机内部地址"
+		}
+	}
+}
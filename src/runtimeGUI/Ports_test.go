@@ -0,0 +1,95 @@
+package runtimeGUI
+
+import (
+	"testing"
+
+	setting "Golang-WSL-GUI/src/Setting"
+)
+
+// 网络脚本的输出: node(10) 监听 127.0.0.1:8080 与 0.0.0.0:3000,python3(11) 监听 [::1]:5001
+const networkOutput = `if eth0 up 1500 00:15:5d:01:02:03
+if lo unknown 65536 00:00:00:00:00:00
+addr 1: lo    inet 127.0.0.1/8 scope host lo\       valid_lft forever preferred_lft forever
+addr 2: eth0    inet 172.20.1.2/20 brd 172.20.15.255 scope global eth0\       valid_lft forever preferred_lft forever
+addr 2: eth0    inet6 fe80::215:5dff:fe01:203/64 scope link \       valid_lft forever preferred_lft forever
+tcp    0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 111 1 0000000000000000 100 0 0 10 0
+tcp    1: 00000000:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 222 1 0000000000000000 100 0 0 10 0
+tcp    2: 0201A8C0:0016 0101A8C0:C350 01 00000000:00000000 00:00000000 00000000  1000        0 333 1 0000000000000000 100 0 0 10 0
+tcp6    0: 00000000000000000000000001000000:1389 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 444 1 0000000000000000 100 0 0 10 0
+udp    0: 3500007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 555 2 0000000000000000 0
+comm 10 node
+comm 11 python3 -m x
+fd /proc/10
+sock 111
+sock 222
+fd /proc/11
+sock 444
+`
+
+func TestParseNetwork(t *testing.T) {
+	r := parseNetwork(networkOutput)
+	if len(r.Interfaces) != 2 || r.Interfaces[0].Name != "eth0" || len(r.Interfaces[0].Addresses) != 2 || r.Interfaces[0].MTU != 1500 {
+		t.Fatalf("网卡 = %+v", r.Interfaces)
+	}
+	// 已建立的TCP连接不算监听
+	if len(r.Sockets) != 4 {
+		t.Fatalf("套接字 = %+v", r.Sockets)
+	}
+	dns, web, api, dev := r.Sockets[0], r.Sockets[1], r.Sockets[2], r.Sockets[3]
+	if dns.Protocol != "udp" || dns.Address != "127.0.0.53" || dns.Port != 53 || dns.PID != 0 {
+		t.Fatalf("udp = %+v", dns)
+	}
+	if web.Address != "0.0.0.0" || web.Port != 3000 || web.Process != "node" {
+		t.Fatalf("tcp 3000 = %+v", web)
+	}
+	if api.Address != "::1" || api.Port != 5001 || api.PID != 11 || api.Process != "python3 -m x" {
+		t.Fatalf("tcp6 = %+v", api)
+	}
+	if dev.Address != "127.0.0.1" || dev.Port != 8080 || dev.PID != 10 {
+		t.Fatalf("tcp 8080 = %+v", dev)
+	}
+}
+
+func TestApplyWslConfig(t *testing.T) {
+	type want struct {
+		reachable bool
+		address   string
+	}
+	cases := []struct {
+		name string
+		cfg  setting.WslNetworkConfig
+		// 依次为 udp 53、tcp 3000、tcp6 5001、tcp 8080
+		want []want
+	}{
+		{"default", setting.DefaultWslNetworkConfig(), []want{
+			{false, ""}, {true, "localhost:3000"}, {true, "localhost:5001"}, {true, "localhost:8080"},
+		}},
+		{"nat without forwarding", setting.WslNetworkConfig{NetworkingMode: "nat"}, []want{
+			{false, ""}, {true, "172.20.1.2:3000"}, {false, ""}, {false, ""},
+		}},
+		{"mirrored", setting.WslNetworkConfig{NetworkingMode: "mirrored", IgnoredPorts: "3000, 9000"}, []want{
+			{true, "localhost:53"}, {false, ""}, {true, "localhost:5001"}, {true, "localhost:8080"},
+		}},
+		{"none", setting.WslNetworkConfig{NetworkingMode: "none", LocalhostForwarding: true}, []want{
+			{false, ""}, {false, ""}, {false, ""}, {false, ""},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := parseNetwork(networkOutput)
+			applyWslConfig(r, c.cfg)
+			if r.NetworkMode != c.cfg.NetworkingMode || r.LocalhostForwarding != c.cfg.LocalhostForwarding {
+				t.Fatalf("report = %s %v", r.NetworkMode, r.LocalhostForwarding)
+			}
+			for i, w := range c.want {
+				s := r.Sockets[i]
+				if s.Reachable != w.reachable || s.WindowsAddress != w.address {
+					t.Fatalf("%s %d: %+v", s.Protocol, s.Port, s)
+				}
+				if !s.Reachable && s.Reason == "" {
+					t.Fatalf("%s %d 不可访问但没有原因", s.Protocol, s.Port)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package runtimeGUI
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"Golang-WSL-GUI/src/wslcli"
+)
+
+// 进程列表的排序字段
+type ProcessSort string
+
+const (
+	SortByCPU     ProcessSort = "cpu"
+	SortByRSS     ProcessSort = "rss"
+	SortByPID     ProcessSort = "pid"
+	SortByUser    ProcessSort = "user"
+	SortByStart   ProcessSort = "start"
+	SortByCommand ProcessSort = "command"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllProcessSorts = []struct {
+	Value  ProcessSort
+	TSName string
+}{
+	{SortByCPU, "CPU"},
+	{SortByRSS, "RSS"},
+	{SortByPID, "PID"},
+	{SortByUser, "User"},
+	{SortByStart, "Start"},
+	{SortByCommand, "Command"},
+}
+
+// 允许发送的信号
+type ProcessSignal string
+
+const (
+	SignalTERM ProcessSignal = "TERM"
+	SignalKILL ProcessSignal = "KILL"
+	SignalINT  ProcessSignal = "INT"
+	SignalHUP  ProcessSignal = "HUP"
+	SignalSTOP ProcessSignal = "STOP"
+	SignalCONT ProcessSignal = "CONT"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllProcessSignals = []struct {
+	Value  ProcessSignal
+	TSName string
+}{
+	{SignalTERM, "TERM"},
+	{SignalKILL, "KILL"},
+	{SignalINT, "INT"},
+	{SignalHUP, "HUP"},
+	{SignalSTOP, "STOP"},
+	{SignalCONT, "CONT"},
+}
+
+// 发行版内的一个进程
+type Process struct {
+	PID  int    `json:"pid"`
+	PPID int    `json:"ppid"`
+	User string `json:"user"`
+	// 完整命令行,内核线程等没有命令行时为 [进程名]
+	Command string `json:"command"`
+	// 采样间隔内的CPU占用百分比,单核满载为100
+	CPUPercent float64 `json:"cpuPercent"`
+	RSSBytes   int64   `json:"rssBytes"`
+	// 启动时间,Unix毫秒
+	StartTime int64  `json:"startTime"`
+	State     string `json:"state"`
+}
+
+// 进程列表的查询条件
+type ProcessQuery struct {
+	// 按PID、用户名或命令行过滤,不区分大小写
+	Filter string      `json:"filter"`
+	SortBy ProcessSort `json:"sortBy"`
+	// 为true时升序,默认降序
+	Ascending bool `json:"ascending"`
+	// 最多返回的条数,0为不限
+	Limit int `json:"limit"`
+}
+
+// 两次读取各进程的 /proc/<pid>/stat,间隔内的CPU时间差即占用率;$$ 为脚本自身。
+// Uid 用 read 内建命令从 status 中读取,不为每个进程启动 sed;
+// 命令行由一次 head 读取全部 cmdline (每个最多4096字节),多个文件时 head 会输出 "==> 文件 <==" 分隔,
+// 加上 /dev/null 保证只有一个进程时也有分隔,再经一次 tr 把参数间的 \0 替换为空格
+const processScript = `hz=$(getconf CLK_TCK 2>/dev/null || echo 100); page=$(getconf PAGESIZE 2>/dev/null || echo 4096); ` +
+	`echo "self $$"; echo "hz $hz"; echo "page $page"; grep '^btime ' /proc/stat; ` +
+	`read -r up0 _ < /proc/uptime; echo "up0 $up0"; ` +
+	`for f in /proc/[0-9]*/stat; do { read -r l < "$f"; } 2>/dev/null && echo "s0 $l"; done; ` +
+	`sleep "$1"; read -r up1 _ < /proc/uptime; echo "up1 $up1"; ` +
+	`for d in /proc/[0-9]*; do { read -r l < "$d/stat"; } 2>/dev/null || continue; echo "s1 $l"; ` +
+	`uid=; { while read -r k v _; do [ "$k" = Uid: ] && { uid=$v; break; }; done < "$d/status"; } 2>/dev/null; ` +
+	`echo "uid ${d#/proc/} $uid"; done; ` +
+	`sed 's/^/passwd /' /etc/passwd; ` +
+	`head -c 4096 /dev/null /proc/[0-9]*/cmdline 2>/dev/null | tr '\0' ' '`
+
+// 计算CPU占用的采样间隔
+const processSampleInterval = time.Second
+
+// 进程列表,按query排序过滤
+func GetProcesses(ctx context.Context, name string, query ProcessQuery) ([]Process, error) {
+	secs := strconv.Itoa(int(processSampleInterval / time.Second))
+	res, err := wslcli.Default.Exec(ctx, name, wslcli.ExecOptions{Timeout: metricsTimeout + processSampleInterval, ReadOnly: true}, "sh", "-c", processScript, "sh", secs)
+	if err != nil {
+		return nil, err
+	}
+	procs := parseProcesses(res.Text())
+	return filterProcesses(procs, query), nil
+}
+
+// /proc/<pid>/stat 中需要的字段
+type procStat struct {
+	pid, ppid   int
+	comm, state string
+	// CPU时间与启动时间,单位为时钟周期
+	ticks, start uint64
+	// 页数
+	rss int64
+}
+
+// 进程名可能包含空格和括号,以最后一个右括号分隔
+func parseProcStat(line string) (procStat, bool) {
+	open, end := strings.IndexByte(line, '('), strings.LastIndexByte(line, ')')
+	if open < 0 || end < open {
+		return procStat{}, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line[:open]))
+	if err != nil {
+		return procStat{}, false
+	}
+	// 右括号后从第3个字段 state 开始
+	rest := strings.Fields(line[end+1:])
+	if len(rest) < 22 {
+		return procStat{}, false
+	}
+	s := procStat{pid: pid, comm: line[open+1 : end], state: rest[0]}
+	s.ppid, _ = strconv.Atoi(rest[1])
+	utime, _ := strconv.ParseUint(rest[11], 10, 64)
+	stime, _ := strconv.ParseUint(rest[12], 10, 64)
+	s.ticks = utime + stime
+	s.start, _ = strconv.ParseUint(rest[19], 10, 64)
+	s.rss, _ = strconv.ParseInt(rest[21], 10, 64)
+	return s, true
+}
+
+func parseProcesses(text string) []Process {
+	hz, page := 100.0, int64(4096)
+	var btime int64
+	self := -1
+	var up0, up1 float64
+	before := map[int]uint64{}
+	var stats []procStat
+	uids, cmds, users := map[int]string{}, map[int]string{}, map[string]string{}
+	// 进入 head 的输出后,非分隔行都属于当前进程的命令行
+	inCmds, cmdPID := false, -1
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if pid, ok := cmdlineHeader(line); ok || inCmds {
+			if ok {
+				inCmds, cmdPID = true, pid
+			} else if cmdPID >= 0 && strings.TrimSpace(line) != "" {
+				cmds[cmdPID] = strings.TrimSpace(cmds[cmdPID] + " " + line)
+			}
+			continue
+		}
+		tag, rest, _ := strings.Cut(line, " ")
+		switch tag {
+		case "hz":
+			if v, err := strconv.ParseFloat(rest, 64); err == nil && v > 0 {
+				hz = v
+			}
+		case "page":
+			if v, err := strconv.ParseInt(rest, 10, 64); err == nil && v > 0 {
+				page = v
+			}
+		case "self":
+			self, _ = strconv.Atoi(rest)
+		case "btime":
+			btime, _ = strconv.ParseInt(strings.TrimSpace(rest), 10, 64)
+		case "up0":
+			up0, _ = strconv.ParseFloat(rest, 64)
+		case "up1":
+			up1, _ = strconv.ParseFloat(rest, 64)
+		case "s0":
+			if s, ok := parseProcStat(rest); ok {
+				before[s.pid] = s.ticks
+			}
+		case "s1":
+			if s, ok := parseProcStat(rest); ok {
+				stats = append(stats, s)
+			}
+		case "uid":
+			pid, value, _ := strings.Cut(rest, " ")
+			if n, err := strconv.Atoi(pid); err == nil {
+				uids[n] = value
+			}
+		case "passwd":
+			// 用户名:密码:UID:...
+			if f := strings.Split(rest, ":"); len(f) > 2 {
+				users[f[2]] = f[0]
+			}
+		}
+	}
+
+	elapsed := up1 - up0
+	hidden := scriptProcesses(stats, self)
+	procs := make([]Process, 0, len(stats))
+	for _, s := range stats {
+		if hidden[s.pid] {
+			continue
+		}
+		p := Process{
+			PID:       s.pid,
+			PPID:      s.ppid,
+			User:      uids[s.pid],
+			Command:   cmds[s.pid],
+			RSSBytes:  s.rss * page,
+			StartTime: btime*1000 + int64(float64(s.start)/hz*1000),
+			State:     s.state,
+		}
+		if name, ok := users[p.User]; ok {
+			p.User = name
+		}
+		if p.Command == "" {
+			p.Command = "[" + s.comm + "]"
+		}
+		// 采样期间启动的进程从其启动时刻算起
+		span, prev := elapsed, before[s.pid]
+		if _, ok := before[s.pid]; !ok {
+			span = up1 - float64(s.start)/hz
+		}
+		if span > 0 && s.ticks >= prev {
+			p.CPUPercent = float64(s.ticks-prev) / hz / span * 100
+		}
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// head 输出的分隔行 "==> /proc/<pid>/cmdline <==",/dev/null 的分隔返回 -1
+func cmdlineHeader(line string) (int, bool) {
+	name, ok := strings.CutPrefix(line, "==> ")
+	if !ok {
+		return 0, false
+	}
+	name, ok = strings.CutSuffix(name, " <==")
+	if !ok {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "/proc/"), "/cmdline"))
+	if err != nil {
+		return -1, true
+	}
+	return pid, true
+}
+
+// 采样脚本自身、其子进程,以及 wsl.exe 为执行脚本创建的 init/relay 父进程链;
+// PID 1 是发行版的 init,不在此列
+func scriptProcesses(stats []procStat, self int) map[int]bool {
+	hidden := map[int]bool{}
+	if self < 0 {
+		return hidden
+	}
+	parent := map[int]int{}
+	for _, s := range stats {
+		parent[s.pid] = s.ppid
+		if s.pid == self || s.ppid == self {
+			hidden[s.pid] = true
+		}
+	}
+	for pid, ok := parent[self]; ok && pid > 1 && !hidden[pid]; pid, ok = parent[pid] {
+		hidden[pid] = true
+	}
+	return hidden
+}
+
+// 过滤、排序并截断,排序字段相同时按PID升序
+func filterProcesses(procs []Process, q ProcessQuery) []Process {
+	out := procs[:0]
+	filter := strings.ToLower(strings.TrimSpace(q.Filter))
+	for _, p := range procs {
+		if filter == "" || strconv.Itoa(p.PID) == filter ||
+			strings.Contains(strings.ToLower(p.User), filter) ||
+			strings.Contains(strings.ToLower(p.Command), filter) {
+			out = append(out, p)
+		}
+	}
+
+	compare := func(a, b Process) int {
+		switch q.SortBy {
+		case SortByRSS:
+			return cmpInt64(a.RSSBytes, b.RSSBytes)
+		case SortByPID:
+			return a.PID - b.PID
+		case SortByUser:
+			return strings.Compare(a.User, b.User)
+		case SortByStart:
+			return cmpInt64(a.StartTime, b.StartTime)
+		case SortByCommand:
+			return strings.Compare(a.Command, b.Command)
+		}
+		switch {
+		case a.CPUPercent < b.CPUPercent:
+			return -1
+		case a.CPUPercent > b.CPUPercent:
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		c := compare(out[i], out[j])
+		if c == 0 {
+			return out[i].PID < out[j].PID
+		}
+		if q.Ascending {
+			return c < 0
+		}
+		return c > 0
+	})
+
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// 以root身份向进程发送信号,只允许白名单中的信号,不允许结束 init
+func KillProcess(ctx context.Context, name string, pid int, signal ProcessSignal) error {
+	allowed := false
+	for _, s := range AllProcessSignals {
+		if s.Value == signal {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("不支持的信号 %q", signal)
+	}
+	if pid <= 1 {
+		return fmt.Errorf("不能向 PID %d 发送信号", pid)
+	}
+	_, err := wslcli.Default.Exec(ctx, name, wslcli.ExecOptions{User: "root", Timeout: metricsTimeout}, "kill", "-s", string(signal), strconv.Itoa(pid))
+	return err
+}
@@ -0,0 +1,103 @@
+package runtimeGUI
+
+import (
+	"context"
+	"testing"
+)
+
+// 采样脚本的输出: 脚本 50 由 wsl.exe 的 init(5)/relay(6) 启动,60 为其 sleep
+const processOutput = `self 50
+hz 100
+page 4096
+btime 1700000000
+up0 100.00
+s0 1 (init) S 0 1 1 0 -1 4194560 1 0 0 0 10 5 0 0 20 0 1 0 1 100 10 0
+s0 5 (init) S 1 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 8000 100 10 0
+s0 6 (init) S 5 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 8000 100 10 0
+s0 7 (my (weird) app) R 1 7 7 0 -1 4194560 1 0 0 0 100 0 0 0 20 0 1 0 500 100 250 0
+s0 50 (sh) S 6 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 9000 100 25 0
+up1 101.00
+s1 1 (init) S 0 1 1 0 -1 4194560 1 0 0 0 10 5 0 0 20 0 1 0 1 100 10 0
+uid 1 0
+s1 5 (init) S 1 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 8000 100 10 0
+uid 5 0
+s1 6 (init) S 5 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 8000 100 10 0
+uid 6 0
+s1 7 (my (weird) app) R 1 7 7 0 -1 4194560 1 0 0 0 150 0 0 0 20 0 1 0 500 100 250 0
+uid 7 1000
+s1 50 (sh) S 6 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 9000 100 25 0
+uid 50 1000
+s1 60 (sleep) S 50 5 5 0 -1 4194560 1 0 0 0 1 0 0 0 20 0 1 0 10000 100 25 0
+uid 60 1000
+passwd root:x:0:0:root:/root:/bin/sh
+passwd alice:x:1000:1000::/home/alice:/bin/bash
+==> /dev/null <==
+
+==> /proc/1/cmdline <==
+/init 
+==> /proc/5/cmdline <==
+/init 
+==> /proc/7/cmdline <==
+python3 -c print(1)
+s1 x 
+==> /proc/50/cmdline <==
+sh -c ... 
+`
+
+func TestParseProcesses(t *testing.T) {
+	ps := filterProcesses(parseProcesses(processOutput), ProcessQuery{})
+	// 脚本、其子进程与 init/relay 父进程链均被过滤,PID 1 保留
+	if len(ps) != 2 {
+		t.Fatalf("进程 = %+v", ps)
+	}
+	p := ps[0]
+	if p.PID != 7 || p.CPUPercent != 50 || p.User != "alice" || p.RSSBytes != 250*4096 || p.StartTime != 1700000005000 {
+		t.Fatalf("进程7 = %+v", p)
+	}
+	// 参数中的换行并入同一条命令行,不会被当作标记行
+	if p.Command != "python3 -c print(1) s1 x" {
+		t.Fatalf("命令行 = %q", p.Command)
+	}
+	if ps[1].PID != 1 || ps[1].User != "root" || ps[1].Command != "/init" {
+		t.Fatalf("进程1 = %+v", ps[1])
+	}
+}
+
+// 内核线程没有命令行,以 [进程名] 表示
+func TestParseProcessesKernelThread(t *testing.T) {
+	out := "self 50\nup0 1\nup1 2\ns1 2 (kthreadd) S 0 0 0 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 2 0 0 0\nuid 2 0\n==> /dev/null <==\n\n==> /proc/2/cmdline <==\n"
+	ps := parseProcesses(out)
+	if len(ps) != 1 || ps[0].Command != "[kthreadd]" {
+		t.Fatalf("进程 = %+v", ps)
+	}
+}
+
+func TestFilterProcesses(t *testing.T) {
+	if f := filterProcesses(parseProcesses(processOutput), ProcessQuery{Filter: "INIT"}); len(f) != 1 || f[0].PID != 1 {
+		t.Fatalf("过滤 = %+v", f)
+	}
+	if f := filterProcesses(parseProcesses(processOutput), ProcessQuery{SortBy: SortByPID, Ascending: true}); f[0].PID != 1 {
+		t.Fatalf("升序 = %+v", f)
+	}
+	if f := filterProcesses(parseProcesses(processOutput), ProcessQuery{Limit: 1}); len(f) != 1 {
+		t.Fatalf("截断 = %+v", f)
+	}
+}
+
+func TestKillProcess(t *testing.T) {
+	f := fakeDefault(t)
+	f.On("-d", "Ubuntu", "-u", "root", "--exec", "kill", "...")
+	ctx := context.Background()
+	if err := KillProcess(ctx, "Ubuntu", 7, "SEGV"); err == nil {
+		t.Fatal("不在白名单中的信号没有返回错误")
+	}
+	if err := KillProcess(ctx, "Ubuntu", 1, SignalKILL); err == nil {
+		t.Fatal("允许结束 init")
+	}
+	if err := KillProcess(ctx, "Ubuntu", 7, SignalKILL); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Calls()) != 1 || f.Called("-d", "Ubuntu", "-u", "root", "--exec", "kill", "-s", "KILL", "7") != 1 {
+		t.Fatalf("calls = %v", f.Calls())
+	}
+}
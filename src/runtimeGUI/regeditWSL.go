@@ -0,0 +1,78 @@
+//go:build windows
+// +build windows
+
+package runtimeGUI
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// WSL占用 / 剩余总空间
+func getFileSize(name string) *DiskBytes {
+	regeditptr, err := Seach_WSL_Regedit_Info(name)
+	if err != nil {
+		return &DiskBytes{}
+	}
+
+	filepath := fmt.Sprintf(`%s\%s`, regeditptr.BasePath, regeditptr.VhdFileName)
+	var use int64
+	if fileInfo, err := os.Stat(filepath); err == nil {
+		use = fileInfo.Size()
+	}
+
+	var total uint64
+	pathPtr, _ := windows.UTF16PtrFromString(regeditptr.BasePath)
+	windows.GetDiskFreeSpaceEx(pathPtr, nil, &total, nil)
+	return &DiskBytes{
+		Used:  use,
+		Total: int64(total),
+	}
+}
+
+func Seach_WSL_Regedit_Info(wsl_name string) (*Regedit_WSL, error) {
+	rootPath := `Software\Microsoft\Windows\CurrentVersion\Lxss`
+
+	// 打开 Lxss
+	k, err := registry.OpenKey(registry.CURRENT_USER, rootPath, registry.READ)
+	if err != nil {
+		return nil, errors.New("无法打开注册表,检查权限")
+	}
+	defer k.Close()
+
+	// 2. 获取所有GUID
+	subKeys, err := k.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, guid := range subKeys {
+		// 逐个打开GUID比对
+		skPath := rootPath + `\` + guid
+		sk, err := registry.OpenKey(registry.CURRENT_USER, skPath, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+
+		name, _, _ := sk.GetStringValue("DistributionName")
+		if name == wsl_name {
+			basePath, _, _ := sk.GetStringValue("BasePath")
+			vhdFile, _, _ := sk.GetStringValue("VhdFileName")
+			oobeVal, _, _ := sk.GetIntegerValue("RunOOBE")
+			sk.Close()
+			return &Regedit_WSL{
+				BasePath:    basePath,
+				VhdFileName: vhdFile,
+				RunOOBE:     oobeVal != 0,
+			}, nil
+
+		}
+		sk.Close()
+	}
+
+	return nil, errors.New("在注册表未找到发行版")
+}
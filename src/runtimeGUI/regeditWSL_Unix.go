@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package runtimeGUI
+
+import "errors"
+
+// WSL占用 / 剩余总空间
+func getFileSize(name string) *DiskBytes { return &DiskBytes{} }
+
+// 读取WSL在注册表的信息
+func Seach_WSL_Regedit_Info(wsl_name string) (*Regedit_WSL, error) {
+	return nil, errors.New("仅支持Windows")
+}
@@ -1,23 +1,14 @@
-//go:build windows
-// +build windows
-
 package runtimeGUI
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
-	setting "Golang-WSL-GUI/src/Setting"
-	"Golang-WSL-GUI/src/installWSL"
-
-	"golang.org/x/sys/windows"
-	"golang.org/x/sys/windows/registry"
+	"Golang-WSL-GUI/src/wslcli"
 )
 
 type DiskBytes struct {
@@ -38,6 +29,10 @@ type Metrics struct {
 	UsedBytes  int64  `json:"usedBytes"`  // 磁盘已用字节 (用于前端 formatBytes 计算)
 	TotalBytes int64  `json:"totalBytes"` // 磁盘总字节
 	Disk       string `json:"disk"`       // 磁盘百分比字符串 (兼容旧逻辑)
+	// 整个WSL2虚拟机,所有发行版共享
+	VM MemoryStats `json:"vm"`
+	// 本发行版
+	Distro MemoryStats `json:"distro"`
 }
 
 type List struct {
@@ -48,8 +43,8 @@ type List struct {
 
 var List_Slice []*List
 
-func GetWSLallStatus() ([]*List, error) {
-	currentList, err := WSLsrtatus()
+func GetWSLallStatus(ctx context.Context) ([]*List, error) {
+	currentList, err := WSLsrtatus(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -62,112 +57,81 @@ func GetWSLallStatus() ([]*List, error) {
 }
 
 // 检测发行版是否在运行
-func WSLsrtatus() ([]*List, error) {
-	cmd := exec.Command("wsl.exe", "--list", "--verbose")
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-	out, err := cmd.Output()
+func WSLsrtatus(ctx context.Context) ([]*List, error) {
+	distros, err := wslcli.Default.List(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	content := installWSL.Reduce_Unicode(out)
-	lines := strings.Split(content, "\n")
-
 	var currentList []*List
-
-	for _, line := range lines[1:] { // 跳过标题行
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		line = strings.TrimPrefix(line, "*")
-		line = strings.TrimSpace(line)
-
-		fields := strings.Fields(line)
-		if len(fields) >= 3 {
-			currentList = append(currentList, &List{
-				Name:    fields[0],
-				Status:  fields[1],             // 存入 "Running" 或 "Stopped"
-				Version: fields[len(fields)-1], // 获取版本号
-			})
-		}
+	for _, d := range distros {
+		currentList = append(currentList, &List{
+			Name:    d.Name,
+			Status:  d.State, // 存入 "Running" 或 "Stopped"
+			Version: strconv.Itoa(d.Version),
+		})
 	}
 
 	return currentList, nil
 }
 
+// 单次采样命令的超时,避免WSL卡死时前端轮询堆积
+const metricsTimeout = 5 * time.Second
+
 // GetMetrics 返回单个发行版的详细数据
-func GetMetrics_Runtime(Info installWSL.WSLinfo) (*Metrics, error) {
-	memtotal := setting.Rading_PerformanceConfig()
-	diskptr := getFileSize(Info)
-	return &Metrics{
-		CPU:        fmt.Sprintf(`%.1f%%`, GetCpuUsageSingleShot(Info)),
-		MemUsed:    fmt.Sprintf(`%.1fGB`, GetDistroMemUsage(Info)),
-		MemTotal:   fmt.Sprintf(`%dGB`, memtotal.MemoryLimit),
-		UsedBytes:  diskptr.Used,
-		TotalBytes: diskptr.Total,
-	}, nil
+func GetMetrics_Runtime(ctx context.Context, name string) (*Metrics, error) {
+	diskptr := getFileSize(name)
+	cpu, err := GetCpuUsageSingleShot(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	vm, distro, err := GetDistroMemUsage(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	m := newMetrics(cpu, vm, distro, diskptr)
+	return &m, nil
 }
 
-// WSL占用 / 剩余总空间
-func getFileSize(Info installWSL.WSLinfo) *DiskBytes {
-	regeditptr, _ := Seach_WSL_Regedit_Info(Info.Linux_Version)
-
-	filepath := fmt.Sprintf(`%s\%s`, regeditptr.BasePath, regeditptr.VhdFileName)
-	fileInfo, _ := os.Stat(filepath)
-	use := fileInfo.Size()
-
-	var total uint64
-	pathPtr, _ := windows.UTF16PtrFromString(regeditptr.BasePath)
-	windows.GetDiskFreeSpaceEx(pathPtr, nil, &total, nil)
-	return &DiskBytes{
-		Used:  use,
-		Total: int64(total),
+// 格式化后的指标,内存显示为发行版用量 / 虚拟机总量
+func newMetrics(cpu float64, vm, distro MemoryStats, disk *DiskBytes) Metrics {
+	return Metrics{
+		CPU:        fmt.Sprintf(`%.1f%%`, cpu),
+		MemUsed:    fmt.Sprintf(`%.1fGB`, float64(distro.Used)/1024/1024/1024),
+		MemTotal:   fmt.Sprintf(`%.1fGB`, float64(vm.Total)/1024/1024/1024),
+		UsedBytes:  disk.Used,
+		TotalBytes: disk.Total,
+		VM:         vm,
+		Distro:     distro,
 	}
 }
 
-// 内存占用
-func GetDistroMemUsage(Info installWSL.WSLinfo) float64 {
-	// memory.current 记录了当前该发行版所在控制组消耗的内存字节数
-	// 这是 Linux 内部最底层的统计方式
-	out, err := installWSL.Start_cmd(Info, "useMem")
+// 内存占用,分别返回整个虚拟机与该发行版的用量
+func GetDistroMemUsage(ctx context.Context, name string) (vm, distro MemoryStats, err error) {
+	res, err := wslcli.Default.Exec(ctx, name, wslcli.ExecOptions{Timeout: metricsTimeout, ReadOnly: true}, "sh", "-c", memScript)
 	if err != nil {
-		return 0.0
+		return vm, distro, err
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	if len(lines) < 2 {
-		return 0.0
-	}
-
-	// 提取数字部分
-	var total, avail float64
-	fmt.Sscanf(lines[0], "MemTotal: %f", &total)
-	fmt.Sscanf(lines[1], "MemAvailable: %f", &avail)
-
-	if total == 0 {
-		return 0.0
+	var m memSample
+	for _, line := range strings.Split(res.Text(), "\n") {
+		m.parseLine(strings.Fields(line))
 	}
-
-	// 计算已用空间 (KB -> GB)
-	usedGB := (total - avail) / 1024.0 / 1024.0
-	return usedGB
+	return m.vm(), m.distro(), nil
 }
 
 // CPU占用
-func GetCpuUsageSingleShot(Info installWSL.WSLinfo) float64 {
+func GetCpuUsageSingleShot(ctx context.Context, name string) (float64, error) {
 	// 定义内部获取快照的辅助闭包
-	getSnap := func() (idle, total uint64) {
-		// 使用 sh -c 配合 head 确保读取最快
-		out, err := installWSL.Start_cmd(Info, "CPUpercent")
+	getSnap := func() (idle, total uint64, err error) {
+		// 只读取第一行确保最快
+		res, err := wslcli.Default.Exec(ctx, name, wslcli.ExecOptions{Timeout: metricsTimeout, ReadOnly: true}, "head", "-n", "1", "/proc/stat")
 		if err != nil {
-			return 0, 0
+			return 0, 0, err
 		}
 
-		fields := strings.Fields(installWSL.Reduce_Unicode(out))
+		fields := strings.Fields(res.Text())
 		if len(fields) < 5 {
-			return 0, 0
+			return 0, 0, nil
 		}
 
 		for i := 1; i < len(fields); i++ {
@@ -181,100 +145,38 @@ func GetCpuUsageSingleShot(Info installWSL.WSLinfo) float64 {
 	}
 
 	// 第一次采样
-	i1, t1 := getSnap()
+	i1, t1, err := getSnap()
+	if err != nil {
+		return 0, err
+	}
 
 	// 睡一秒
 	time.Sleep(1 * time.Second)
 
 	// 第二次采样
-	i2, t2 := getSnap()
+	i2, t2, err := getSnap()
+	if err != nil {
+		return 0, err
+	}
 
 	// 计算差值
 	totalDiff := t2 - t1
 	idleDiff := i2 - i1
-
-	return float64(totalDiff-idleDiff) / float64(totalDiff) * 100
-}
-
-func Seach_WSL_Regedit_Info(wsl_name string) (*Regedit_WSL, error) {
-	rootPath := `Software\Microsoft\Windows\CurrentVersion\Lxss`
-
-	// 打开 Lxss
-	k, err := registry.OpenKey(registry.CURRENT_USER, rootPath, registry.READ)
-	if err != nil {
-		return nil, errors.New("无法打开注册表,检查权限")
-	}
-	defer k.Close()
-
-	// 2. 获取所有GUID
-	subKeys, err := k.ReadSubKeyNames(-1)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, guid := range subKeys {
-		// 逐个打开GUID比对
-		skPath := rootPath + `\` + guid
-		sk, err := registry.OpenKey(registry.CURRENT_USER, skPath, registry.QUERY_VALUE)
-		if err != nil {
-			continue
-		}
-
-		name, _, _ := sk.GetStringValue("DistributionName")
-		if name == wsl_name {
-			basePath, _, _ := sk.GetStringValue("BasePath")
-			vhdFile, _, _ := sk.GetStringValue("VhdFileName")
-			oobeVal, _, _ := sk.GetIntegerValue("RunOOBE")
-			sk.Close()
-			return &Regedit_WSL{
-				BasePath:    basePath,
-				VhdFileName: vhdFile,
-				RunOOBE:     oobeVal != 0,
-			}, nil
-
-		}
-		sk.Close()
+	if totalDiff == 0 {
+		return 0, nil
 	}
 
-	return nil, errors.New("在注册表未找到发行版")
+	return float64(totalDiff-idleDiff) / float64(totalDiff) * 100, nil
 }
 
-func GetDefaultUser(Info installWSL.WSLinfo) (string, error) {
-	line, err := installWSL.Start_cmd(Info, "SeachUser")
+func GetDefaultUser(ctx context.Context, name string) (string, error) {
+	conf, err := wslcli.ReadWslConf(ctx, wslcli.Default, name)
 	if err != nil {
-		return installWSL.Reduce_Unicode(line), err
+		return "", err
 	}
-
-	cleanStr := installWSL.Reduce_Unicode(line)
-
-	// 解析逻辑
-	lines := strings.Split(cleanStr, "\n")
-	var isUserSection bool
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// 匹配段落标签 [user]
-		if strings.EqualFold(line, "[user]") {
-			isUserSection = true
-			continue
-		}
-
-		// 如果在 [user] 段下找到了 default=xxx
-		if isUserSection {
-			// 如果遇到下一个段落 [xxx]，说明 user 段结束了
-			if strings.HasPrefix(line, "[") {
-				break
-			}
-
-			if strings.Contains(line, "=") {
-				parts := strings.SplitN(line, "=", 2)
-				if strings.EqualFold(strings.TrimSpace(parts[0]), "default") {
-					return strings.TrimSpace(parts[1]), nil
-				}
-			}
-		}
+	// 存在多个 [user] 段时以最后一个为准
+	if user, ok := conf.Get("user", "default"); ok && user != "" {
+		return user, nil
 	}
-
 	return "未发现配置默认用户", errors.New("未发现配置默认用户")
 }
@@ -0,0 +1,341 @@
+package wslcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	audit "Golang-WSL-GUI/src/Audit"
+)
+
+// 发行版运行状态
+const (
+	StateRunning    = "Running"
+	StateStopped    = "Stopped"
+	StateInstalling = "Installing"
+	StateConverting = "Converting"
+)
+
+// wsl.exe 封装,所有WSL操作都通过该接口执行
+type Client interface {
+	// 已注册的发行版及其状态,对应 wsl -l -v
+	List(ctx context.Context) ([]Distro, error)
+	// 结束单个发行版,对应 wsl -t
+	Terminate(ctx context.Context, name string) error
+	// 关闭所有发行版及WSL2虚拟机,对应 wsl --shutdown
+	Shutdown(ctx context.Context) error
+	// 导出发行版到tar文件
+	Export(ctx context.Context, name, file string, opts ExportOptions) error
+	// 从tar/vhdx文件导入发行版
+	Import(ctx context.Context, name, installDir, file string, opts ImportOptions) error
+	// 注销发行版并删除其磁盘
+	Unregister(ctx context.Context, name string) error
+	// 在发行版中执行命令,argv不经过shell解析
+	Exec(ctx context.Context, name string, opts ExecOptions, argv ...string) (Result, error)
+	// 在发行版中启动常驻命令,标准输出实时写入w,默认不限时,ctx取消时结束进程
+	Stream(ctx context.Context, name string, opts ExecOptions, w io.Writer, argv ...string) error
+	// 启动发行版默认shell后立即退出,用于唤醒发行版
+	Launch(ctx context.Context, name string) error
+	// WSL及各组件版本,对应 wsl --version
+	Version(ctx context.Context) (VersionInfo, error)
+}
+
+// 发行版信息
+type Distro struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Version int    `json:"version"`
+	Default bool   `json:"default"`
+}
+
+type ExportOptions struct {
+	// 导出为vhdx而不是tar
+	VHD bool
+}
+
+type ImportOptions struct {
+	// WSL版本,0时使用默认值2
+	Version int
+	// file为vhdx时直接作为磁盘使用
+	VHD bool
+}
+
+type ExecOptions struct {
+	// 以指定用户执行,为空时使用默认用户
+	User string
+	// 工作目录,Linux路径
+	Dir string
+	// 标准输入,为空时不提供输入
+	Stdin io.Reader
+	// 覆盖默认超时,小于0时不限时
+	Timeout time.Duration
+	// 只读查询,审计日志中做标记
+	ReadOnly bool
+}
+
+// 命令执行结果,Output为合并后的标准输出与错误输出
+type Result struct {
+	Output   []byte
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// wsl --version 的各项版本
+type VersionInfo struct {
+	WSL      string `json:"wsl"`
+	Kernel   string `json:"kernel"`
+	WSLg     string `json:"wslg"`
+	MSRDC    string `json:"msrdc"`
+	Direct3D string `json:"direct3d"`
+	DXCore   string `json:"dxcore"`
+	Windows  string `json:"windows"`
+	// 原始输出
+	Output []byte `json:"-"`
+}
+
+// 默认客户端
+var Default Client = New(AuditRunner{Next: ExecRunner{Path: "wsl.exe"}, Program: "wsl.exe"})
+
+// 按 wsl.exe 命令行参数实现的客户端
+type ExeClient struct {
+	Runner   Runner
+	Timeouts Timeouts
+}
+
+func New(r Runner) *ExeClient {
+	return &ExeClient{Runner: r, Timeouts: DefaultTimeouts}
+}
+
+// 执行 wsl.exe,超时或ctx取消时结束进程树
+func (c *ExeClient) run(ctx context.Context, timeout time.Duration, stdin io.Reader, args ...string) (Result, error) {
+	return c.runCommand(ctx, timeout, Command{Args: args, Stdin: stdin})
+}
+
+func (c *ExeClient) runCommand(ctx context.Context, timeout time.Duration, cmd Command) (Result, error) {
+	runCtx, cancel, limit := withTimeout(ctx, timeout)
+	defer cancel()
+
+	op := "wsl.exe " + strings.Join(audit.Redact(cmd.Args), " ")
+	res, err := c.Runner.Run(runCtx, cmd)
+	if runCtx.Err() == context.DeadlineExceeded {
+		return res, &TimeoutError{Op: op, Timeout: limit}
+	}
+	if ctx.Err() != nil {
+		return res, ctx.Err()
+	}
+	if err != nil {
+		return res, newError(op, res.Text(), res.ExitCode, err)
+	}
+	return res, nil
+}
+
+func (c *ExeClient) List(ctx context.Context) ([]Distro, error) {
+	res, err := c.runCommand(ctx, c.Timeouts.List, Command{Args: []string{"--list", "--verbose"}, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return parseList(res.Text()), nil
+}
+
+func (c *ExeClient) Terminate(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	_, err := c.run(ctx, c.Timeouts.Terminate, nil, "--terminate", name)
+	return err
+}
+
+func (c *ExeClient) Shutdown(ctx context.Context) error {
+	_, err := c.run(ctx, c.Timeouts.Shutdown, nil, "--shutdown")
+	return err
+}
+
+func (c *ExeClient) Export(ctx context.Context, name, file string, opts ExportOptions) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	args := []string{"--export", name, file}
+	if opts.VHD {
+		args = append(args, "--vhd")
+	}
+	_, err := c.run(ctx, c.Timeouts.Export, nil, args...)
+	return err
+}
+
+func (c *ExeClient) Import(ctx context.Context, name, installDir, file string, opts ImportOptions) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	version := opts.Version
+	if version == 0 {
+		version = 2
+	}
+	args := []string{"--import", name, installDir, file, "--version", strconv.Itoa(version)}
+	if opts.VHD {
+		args = append(args, "--vhd")
+	}
+	_, err := c.run(ctx, c.Timeouts.Import, nil, args...)
+	return err
+}
+
+func (c *ExeClient) Unregister(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	_, err := c.run(ctx, c.Timeouts.Unregister, nil, "--unregister", name)
+	return err
+}
+
+func (c *ExeClient) Exec(ctx context.Context, name string, opts ExecOptions, argv ...string) (Result, error) {
+	if err := checkName(name); err != nil {
+		return Result{}, err
+	}
+	if len(argv) == 0 {
+		return Result{}, errors.New("没有要执行的命令")
+	}
+	timeout := c.Timeouts.Exec
+	if opts.Timeout != 0 {
+		timeout = opts.Timeout
+	}
+	return c.runCommand(ctx, timeout, Command{Args: ExecArgs(name, opts, argv...), Stdin: opts.Stdin, ReadOnly: opts.ReadOnly})
+}
+
+func (c *ExeClient) Stream(ctx context.Context, name string, opts ExecOptions, w io.Writer, argv ...string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if len(argv) == 0 {
+		return errors.New("没有要执行的命令")
+	}
+	_, err := c.runCommand(ctx, opts.Timeout, Command{Args: ExecArgs(name, opts, argv...), Stdin: opts.Stdin, Stdout: w, ReadOnly: opts.ReadOnly})
+	return err
+}
+
+func (c *ExeClient) Launch(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	_, err := c.run(ctx, c.Timeouts.Launch, nil, "-d", name)
+	return err
+}
+
+func (c *ExeClient) Version(ctx context.Context) (VersionInfo, error) {
+	res, err := c.runCommand(ctx, c.Timeouts.Version, Command{Args: []string{"--version"}, ReadOnly: true})
+	if err != nil {
+		return VersionInfo{Output: res.Output}, err
+	}
+	info := parseVersion(res.Text())
+	info.Output = res.Output
+	return info, nil
+}
+
+// Exec 对应的 wsl.exe 参数
+func ExecArgs(name string, opts ExecOptions, argv ...string) []string {
+	args := []string{"-d", name}
+	if opts.User != "" {
+		args = append(args, "-u", opts.User)
+	}
+	if opts.Dir != "" {
+		args = append(args, "--cd", opts.Dir)
+	}
+	args = append(args, "--exec")
+	return append(args, argv...)
+}
+
+// 发行版名称不能为空,也不能以-开头被当作参数
+func checkName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("发行版名称无效: %q", name)
+	}
+	return nil
+}
+
+// 解析 wsl -l -v 输出,第一行为标题,默认发行版以*标记
+func parseList(content string) []Distro {
+	lines := strings.Split(content, "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+
+	var list []Distro
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		isDefault := strings.HasPrefix(line, "*")
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		// 本地化状态可能由多个单词组成,如 Wird ausgeführt
+		version, _ := strconv.Atoi(fields[len(fields)-1])
+		list = append(list, Distro{
+			Name:    fields[0],
+			State:   normalizeState(strings.Join(fields[1:len(fields)-1], " ")),
+			Version: version,
+			Default: isDefault,
+		})
+	}
+	return list
+}
+
+// 解析 wsl --version 输出,标签可能被本地化,无法识别时按固定行序归类
+func parseVersion(content string) VersionInfo {
+	var info VersionInfo
+	fields := []*string{&info.WSL, &info.Kernel, &info.WSLg, &info.MSRDC, &info.Direct3D, &info.DXCore, &info.Windows}
+	// 按顺序匹配,wslg 需先于 wsl
+	labels := []versionLabel{
+		{[]string{"wslg"}, &info.WSLg},
+		{[]string{"msrdc"}, &info.MSRDC},
+		{[]string{"direct3d"}, &info.Direct3D},
+		{[]string{"dxcore"}, &info.DXCore},
+		{[]string{"windows"}, &info.Windows},
+		{[]string{"kernel", "内核", "核心", "カーネル"}, &info.Kernel},
+		{[]string{"wsl"}, &info.WSL},
+	}
+
+	index := 0
+	for _, line := range strings.Split(content, "\n") {
+		// 日文等系统使用全角冒号
+		line = strings.ReplaceAll(line, "：", ":")
+		label, value, ok := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+		target := labelTarget(strings.ToLower(label), labels)
+		if target == nil && index < len(fields) {
+			target = fields[index]
+		}
+		if target != nil {
+			*target = value
+		}
+		index++
+	}
+	return info
+}
+
+// wsl --version 的标签关键字
+type versionLabel struct {
+	keys   []string
+	target *string
+}
+
+func labelTarget(label string, labels []versionLabel) *string {
+	for _, l := range labels {
+		for _, key := range l.keys {
+			if strings.Contains(label, key) {
+				return l.target
+			}
+		}
+	}
+	return nil
+}
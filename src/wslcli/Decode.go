@@ -0,0 +1,97 @@
+package wslcli
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// 解码 wsl.exe 输出
+// wsl.exe 自身的提示默认为UTF-16LE,设置 WSL_UTF8=1 或执行发行版内命令时为UTF-8,
+// 按BOM与NUL字节分布识别编码,去除BOM与NUL,换行统一为\n
+func DecodeOutput(b []byte) string {
+	var s string
+	switch {
+	case len(b) >= 2 && b[0] == 0xFF && b[1] == 0xFE:
+		s = decodeUTF16LE(b[2:])
+	case len(b) >= 3 && b[0] == 0xEF && b[1] == 0xBB && b[2] == 0xBF:
+		s = string(b[3:])
+	case isUTF16LE(b):
+		s = decodeUTF16LE(b)
+	default:
+		s = string(b)
+	}
+
+	s = strings.ReplaceAll(s, "\x00", "")
+	s = strings.TrimPrefix(s, "\uFEFF")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+// 解码后的输出文本
+func (r Result) Text() string {
+	return DecodeOutput(r.Output)
+}
+
+// 没有BOM时判断是否为UTF-16LE:
+// ASCII字符的高字节为0,奇数位NUL明显多于偶数位;纯非ASCII文本则不是合法UTF-8
+func isUTF16LE(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	var odd, even int
+	for i, c := range b {
+		if c != 0 {
+			continue
+		}
+		if i%2 == 1 {
+			odd++
+		} else {
+			even++
+		}
+	}
+	if odd > even && odd*8 >= len(b)/2 {
+		return true
+	}
+	return odd == 0 && even == 0 && len(b)%2 == 0 && !utf8.Valid(b)
+}
+
+func decodeUTF16LE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])|uint16(b[i+1])<<8)
+	}
+	return string(utf16.Decode(units))
+}
+
+// 本地化的发行版状态,wsl -l -v 在非英文系统上会输出翻译后的状态
+var stateAliases = map[string]string{
+	"running":          StateRunning,
+	"正在运行":             StateRunning,
+	"運行中":              StateRunning,
+	"実行中":              StateRunning,
+	"wird ausgeführt":  StateRunning,
+	"stopped":          StateStopped,
+	"已停止":              StateStopped,
+	"已停止运行":            StateStopped,
+	"停止済み":             StateStopped,
+	"停止":               StateStopped,
+	"beendet":          StateStopped,
+	"angehalten":       StateStopped,
+	"installing":       StateInstalling,
+	"正在安装":             StateInstalling,
+	"インストール中":          StateInstalling,
+	"wird installiert": StateInstalling,
+	"converting":       StateConverting,
+	"正在转换":             StateConverting,
+	"変換中":              StateConverting,
+	"wird konvertiert": StateConverting,
+}
+
+// 将本地化状态统一为英文常量,无法识别时原样返回
+func normalizeState(s string) string {
+	if state, ok := stateAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return state
+	}
+	return s
+}
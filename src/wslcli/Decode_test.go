@@ -0,0 +1,75 @@
+package wslcli
+
+import "testing"
+
+// 带BOM的UTF-16LE
+func bomUTF16(s string) []byte { return append([]byte{0xff, 0xfe}, EncodeUTF16LE(s)...) }
+
+func TestParseLocalizedList(t *testing.T) {
+	zh := "  NAME            STATE           VERSION\r\n* Ubuntu-24.04    正在运行         2\r\n  Debian          已停止         2\r\n"
+	de := "  NAME      STATUS             VERSION\r\n* Ubuntu    Wird ausgeführt    2\r\n  Arch      Beendet            1\r\n"
+	ja := "  NAME      STATE    VERSION\r\n* Ubuntu    実行中    2\r\n  ユーザー名    停止済み    2\r\n"
+	cases := []struct {
+		name string
+		in   []byte
+		want []Distro
+	}{
+		{"zhBOM", bomUTF16(zh), []Distro{{Name: "Ubuntu-24.04", State: StateRunning, Version: 2, Default: true}, {Name: "Debian", State: StateStopped, Version: 2}}},
+		{"zh", EncodeUTF16LE(zh), []Distro{{Name: "Ubuntu-24.04", State: StateRunning, Version: 2, Default: true}, {Name: "Debian", State: StateStopped, Version: 2}}},
+		{"zhUTF8", []byte(zh), []Distro{{Name: "Ubuntu-24.04", State: StateRunning, Version: 2, Default: true}, {Name: "Debian", State: StateStopped, Version: 2}}},
+		{"de", EncodeUTF16LE(de), []Distro{{Name: "Ubuntu", State: StateRunning, Version: 2, Default: true}, {Name: "Arch", State: StateStopped, Version: 1}}},
+		{"ja", EncodeUTF16LE(ja), []Distro{{Name: "Ubuntu", State: StateRunning, Version: 2, Default: true}, {Name: "ユーザー名", State: StateStopped, Version: 2}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := parseList(DecodeOutput(c.in))
+			if len(got) != len(c.want) {
+				t.Fatalf("got %+v", got)
+			}
+			for i := range c.want {
+				g, w := got[i], c.want[i]
+				if g.Name != w.Name || g.State != w.State || g.Version != w.Version || g.Default != w.Default {
+					t.Fatalf("[%d] = %+v, 期望 %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLocalizedVersion(t *testing.T) {
+	cases := map[string]string{
+		"zh": "WSL 版本: 2.3.26.0\r\n内核版本: 5.15.167.4-1\r\nWSLg 版本: 1.0.65\r\nMSRDC 版本: 1.2.5620\r\nDirect3D 版本: 1.611.1-81528511\r\nDXCore 版本: 10.0.26100.1-240331-1435.ge-release\r\nWindows 版本: 10.0.22631.4460\r\n",
+		"ja": "WSL バージョン: 2.3.26.0\r\nカーネル バージョン: 5.15.167.4-1\r\nWSLg バージョン: 1.0.65\r\nMSRDC バージョン: 1.2.5620\r\nDirect3D バージョン: 1.611.1-81528511\r\nDXCore バージョン: 10.0.26100.1\r\nWindows バージョン: 10.0.22631.4460\r\n",
+		"de": "WSL-Version: 2.3.26.0\r\nKernelversion: 5.15.167.4-1\r\nWSLg-Version: 1.0.65\r\nMSRDC-Version: 1.2.5620\r\nDirect3D-Version: 1.611.1-81528511\r\nDXCore-Version: 10.0.26100.1\r\nWindows-Version: 10.0.22631.4460\r\n",
+	}
+	for name, in := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := parseVersion(DecodeOutput(EncodeUTF16LE(in)))
+			if v.WSL != "2.3.26.0" || v.Kernel != "5.15.167.4-1" || v.WSLg != "1.0.65" || v.Windows != "10.0.22631.4460" {
+				t.Fatalf("%+v", v)
+			}
+		})
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []byte
+		want string
+	}{
+		{"zhError", EncodeUTF16LE("不存在具有所提供名称的分发。\r\n错误代码: Wsl/Service/WSL_E_DISTRO_NOT_FOUND\r\n"), "不存在具有所提供名称的分发。\n错误代码: Wsl/Service/WSL_E_DISTRO_NOT_FOUND\n"},
+		// 没有ASCII字符可供判断
+		{"zhOnly", EncodeUTF16LE("中文名"), "中文名"},
+		// Linux 命令输出UTF-8
+		{"utf8", []byte("Grüße\r\n用户\n"), "Grüße\n用户\n"},
+		{"utf8BOM", []byte{0xEF, 0xBB, 0xBF, 'a', '\r', '\n'}, "a\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := DecodeOutput(c.in); got != c.want {
+				t.Fatalf("got %q, 期望 %q", got, c.want)
+			}
+		})
+	}
+}
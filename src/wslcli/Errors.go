@@ -0,0 +1,233 @@
+package wslcli
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// 错误分类
+type ErrorCategory string
+
+const (
+	CategoryUnknown                ErrorCategory = "unknown"
+	CategoryNotFound               ErrorCategory = "not-found"
+	CategoryAlreadyExists          ErrorCategory = "already-exists"
+	CategoryNeedsElevation         ErrorCategory = "needs-elevation"
+	CategoryVirtualizationDisabled ErrorCategory = "virtualization-disabled"
+	CategoryDiskFull               ErrorCategory = "disk-full"
+	CategoryAccessDenied           ErrorCategory = "access-denied"
+	CategoryKernelMissing          ErrorCategory = "kernel-missing"
+	CategoryTimeout                ErrorCategory = "timeout"
+)
+
+// 供 wails EnumBind 生成前端枚举
+var AllErrorCategories = []struct {
+	Value  ErrorCategory
+	TSName string
+}{
+	{CategoryUnknown, "Unknown"},
+	{CategoryNotFound, "NotFound"},
+	{CategoryAlreadyExists, "AlreadyExists"},
+	{CategoryNeedsElevation, "NeedsElevation"},
+	{CategoryVirtualizationDisabled, "VirtualizationDisabled"},
+	{CategoryDiskFull, "DiskFull"},
+	{CategoryAccessDenied, "AccessDenied"},
+	{CategoryKernelMissing, "KernelMissing"},
+	{CategoryTimeout, "Timeout"},
+}
+
+// wsl.exe 执行失败,序列化后供前端展示
+type Error struct {
+	Category ErrorCategory `json:"category"`
+	// 错误代码,如 Wsl/Service/WSL_E_DISTRO_NOT_FOUND、HCS_E_HYPERV_NOT_INSTALLED、0x80070070
+	Code string `json:"code"`
+	// wsl.exe 输出的提示
+	Message string `json:"message"`
+	// 处理建议
+	Hint     string `json:"hint"`
+	ExitCode int    `json:"exitCode"`
+	// 执行的命令
+	Op string `json:"op"`
+
+	err error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.err != nil {
+		msg = e.err.Error()
+	}
+	if e.Op == "" {
+		return msg
+	}
+	return e.Op + ": " + msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// 是否为指定分类的错误
+func IsCategory(err error, c ErrorCategory) bool {
+	if e := Classify(err); e != nil {
+		return e.Category == c
+	}
+	return false
+}
+
+// 取出结构化错误,超时错误转换为 CategoryTimeout,其余错误返回nil
+func Classify(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return &Error{
+			Category: CategoryTimeout,
+			Code:     TimeoutCode,
+			Message:  timeoutErr.Error(),
+			Hint:     categoryHints[CategoryTimeout],
+			Op:       timeoutErr.Op,
+			err:      err,
+		}
+	}
+	return nil
+}
+
+// 面向用户的错误描述,附带处理建议,不包含可能带有敏感参数的命令行
+func Describe(err error) string {
+	e := Classify(err)
+	if e == nil {
+		return err.Error()
+	}
+	msg := e.Message
+	if msg == "" {
+		msg = e.Error()
+	}
+	if e.Hint == "" {
+		return msg
+	}
+	return msg + "\n" + e.Hint
+}
+
+var (
+	// Error code: Wsl/Service/RegisterDistro/ERROR_ALREADY_EXISTS,标签可能被本地化
+	wslCodePattern = regexp.MustCompile(`Wsl(?:/[A-Za-z0-9_]+)+`)
+	symbolPattern  = regexp.MustCompile(`\b(?:HCS_E|WSL_E|ERROR|E)_[A-Z0-9_]+\b`)
+	hresultPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{8}\b`)
+)
+
+// 错误代码对应的分类,HRESULT 使用小写
+var errorCategories = map[string]ErrorCategory{
+	"WSL_E_DISTRO_NOT_FOUND":         CategoryNotFound,
+	"WSL_E_DEFAULT_DISTRO_NOT_FOUND": CategoryNotFound,
+	"ERROR_FILE_NOT_FOUND":           CategoryNotFound,
+	"ERROR_PATH_NOT_FOUND":           CategoryNotFound,
+	"0x80070002":                     CategoryNotFound,
+	"0x80070003":                     CategoryNotFound,
+
+	"ERROR_ALREADY_EXISTS": CategoryAlreadyExists,
+	"ERROR_FILE_EXISTS":    CategoryAlreadyExists,
+	"0x800700b7":           CategoryAlreadyExists,
+	"0x80070050":           CategoryAlreadyExists,
+
+	"ERROR_ELEVATION_REQUIRED": CategoryNeedsElevation,
+	"0x800702e4":               CategoryNeedsElevation,
+
+	"HCS_E_HYPERV_NOT_INSTALLED":              CategoryVirtualizationDisabled,
+	"HCS_E_SERVICE_NOT_AVAILABLE":             CategoryVirtualizationDisabled,
+	"WSL_E_VIRTUAL_MACHINE_PLATFORM_REQUIRED": CategoryVirtualizationDisabled,
+	"ERROR_LINUX_SUBSYSTEM_NOT_PRESENT":       CategoryVirtualizationDisabled,
+	"0x80370102":                              CategoryVirtualizationDisabled,
+	"0x8007019e":                              CategoryVirtualizationDisabled,
+
+	"ERROR_DISK_FULL":        CategoryDiskFull,
+	"ERROR_HANDLE_DISK_FULL": CategoryDiskFull,
+	"0x80070070":             CategoryDiskFull,
+	"0x80070027":             CategoryDiskFull,
+
+	"E_ACCESSDENIED":          CategoryAccessDenied,
+	"ERROR_ACCESS_DENIED":     CategoryAccessDenied,
+	"ERROR_SHARING_VIOLATION": CategoryAccessDenied,
+	"0x80070005":              CategoryAccessDenied,
+	"0x80070020":              CategoryAccessDenied,
+
+	"WSL_E_CUSTOM_KERNEL_NOT_FOUND": CategoryKernelMissing,
+	"WSL_E_WSL2_NEEDS_KERNEL":       CategoryKernelMissing,
+	"0x800701bc":                    CategoryKernelMissing,
+}
+
+var categoryHints = map[ErrorCategory]string{
+	CategoryNotFound:               "发行版或文件不存在,请刷新列表或检查路径",
+	CategoryAlreadyExists:          "同名发行版或文件已存在,请更换名称或先卸载原发行版",
+	CategoryNeedsElevation:         "需要管理员权限,请以管理员身份运行本程序",
+	CategoryVirtualizationDisabled: "请在BIOS中开启虚拟化,并在Windows功能中启用“虚拟机平台”与“适用于Linux的Windows子系统”",
+	CategoryDiskFull:               "磁盘空间不足,请清理磁盘或更换安装位置",
+	CategoryAccessDenied:           "文件被占用或没有访问权限,请关闭占用该文件的程序后重试",
+	CategoryKernelMissing:          "WSL2内核缺失或需要更新,请执行 wsl --update",
+	CategoryTimeout:                "WSL 长时间无响应,可尝试执行 wsl --shutdown 后重试",
+}
+
+// 根据输出与退出码构造结构化错误
+func newError(op string, output string, exitCode int, err error) *Error {
+	e := &Error{
+		Category: CategoryUnknown,
+		Message:  strings.TrimSpace(output),
+		ExitCode: exitCode,
+		Op:       op,
+		err:      err,
+	}
+	e.Code, e.Category = parseErrorCode(output)
+	e.Hint = categoryHints[e.Category]
+	return e
+}
+
+// 提取错误代码并归类,依次尝试 Wsl/... 路径中的各段、独立的符号名与HRESULT
+func parseErrorCode(output string) (string, ErrorCategory) {
+	code := wslCodePattern.FindString(output)
+	if code != "" {
+		segments := strings.Split(code, "/")
+		for i := len(segments) - 1; i > 0; i-- {
+			if c, ok := lookupCategory(segments[i]); ok {
+				return code, c
+			}
+		}
+	}
+	for _, m := range symbolPattern.FindAllString(output, -1) {
+		if c, ok := lookupCategory(m); ok {
+			return firstNonEmpty(code, m), c
+		}
+	}
+	for _, m := range hresultPattern.FindAllString(output, -1) {
+		if c, ok := lookupCategory(m); ok {
+			return firstNonEmpty(code, m), c
+		}
+	}
+	// 旧版 wsl.exe 没有错误代码,只能匹配提示
+	if strings.Contains(strings.ToLower(output), "elevation") {
+		return code, CategoryNeedsElevation
+	}
+	if code == "" {
+		code = firstNonEmpty(symbolPattern.FindString(output), hresultPattern.FindString(output))
+	}
+	return code, CategoryUnknown
+}
+
+func lookupCategory(code string) (ErrorCategory, bool) {
+	if strings.HasPrefix(code, "0x") || strings.HasPrefix(code, "0X") {
+		code = strings.ToLower(code)
+	}
+	c, ok := errorCategories[code]
+	return c, ok
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package wslcli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		out  string
+		want ErrorCategory
+		code string
+	}{
+		{"There is no distribution with the supplied name.\r\nError code: Wsl/Service/WSL_E_DISTRO_NOT_FOUND\r\n", CategoryNotFound, "Wsl/Service/WSL_E_DISTRO_NOT_FOUND"},
+		// 本地化的提示与标签
+		{"不存在具有所提供名称的分发。\r\n错误代码: Wsl/Service/WSL_E_DISTRO_NOT_FOUND\r\n", CategoryNotFound, "Wsl/Service/WSL_E_DISTRO_NOT_FOUND"},
+		{"A distribution with the supplied name already exists.\r\nError code: Wsl/Service/RegisterDistro/ERROR_ALREADY_EXISTS\r\n", CategoryAlreadyExists, ""},
+		{"Please enable the Virtual Machine Platform Windows feature.\r\nFehlercode: Wsl/Service/CreateInstance/CreateVm/HCS_E_HYPERV_NOT_INSTALLED\r\n", CategoryVirtualizationDisabled, ""},
+		{"WslRegisterDistribution failed with error: 0x8007019e\r\n", CategoryVirtualizationDisabled, ""},
+		{"エラー コード: Wsl/Service/RegisterDistro/0x80070070\r\n", CategoryDiskFull, ""},
+		{"Error code: Wsl/Service/E_ACCESSDENIED", CategoryAccessDenied, ""},
+		{"WSL 2 requires an update to its kernel component. 0x800701BC", CategoryKernelMissing, ""},
+		{"The requested operation requires elevation.", CategoryNeedsElevation, ""},
+		{"useradd: user 'bob' already exists", CategoryUnknown, ""},
+	}
+	for _, c := range cases {
+		f := NewFakeRunner()
+		f.On("--unregister", "X").Reply(FakeResponse{Stdout: c.out, ExitCode: 1, UTF16: true})
+		err := New(f).Unregister(context.Background(), "X")
+		e := Classify(err)
+		if e == nil || e.Category != c.want {
+			t.Fatalf("%q => %+v, 期望 %s", c.out, e, c.want)
+		}
+		if c.code != "" && e.Code != c.code {
+			t.Fatalf("%q => Code %q, 期望 %q", c.out, e.Code, c.code)
+		}
+		if e.ExitCode != 1 {
+			t.Fatalf("ExitCode = %d", e.ExitCode)
+		}
+	}
+}
+
+func TestClassifyTimeout(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("--terminate", "X").Reply(FakeResponse{Delay: time.Second})
+	c := New(f)
+	c.Timeouts.Terminate = 20 * time.Millisecond
+
+	err := c.Terminate(context.Background(), "X")
+	if !IsCategory(err, CategoryTimeout) {
+		t.Fatalf("err = %v, 期望超时分类", err)
+	}
+	if Classify(errors.New("plain")) != nil {
+		t.Fatal("普通错误不应被分类")
+	}
+}
+
+// 描述附带处理建议,不包含命令行
+func TestDescribe(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("--unregister", "X").Reply(FakeResponse{Stdout: "The requested operation requires elevation.", ExitCode: 1, UTF16: true})
+	err := New(f).Unregister(context.Background(), "X")
+
+	msg := Describe(err)
+	e := Classify(err)
+	if !strings.HasPrefix(msg, e.Message) || e.Hint == "" || !strings.Contains(msg, e.Hint) {
+		t.Fatalf("Describe = %q", msg)
+	}
+	if strings.Contains(msg, "--unregister") {
+		t.Fatalf("描述中包含命令行: %q", msg)
+	}
+	if got := Describe(errors.New("plain")); got != "plain" {
+		t.Fatalf("Describe = %q", got)
+	}
+}
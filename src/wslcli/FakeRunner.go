@@ -0,0 +1,224 @@
+package wslcli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// 模拟 wsl.exe 的执行器,按参数返回预设输出并记录所有调用,供没有Windows的环境测试
+type FakeRunner struct {
+	// 没有匹配规则时的响应,为空时以退出码1结束
+	Fallback *FakeResponse
+
+	mu    sync.Mutex
+	rules []*FakeRule
+	calls []FakeCall
+}
+
+// 预设的命令输出
+type FakeResponse struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// 按 wsl.exe 自身的方式将输出编码为UTF-16LE,发行版内命令的输出保持UTF-8
+	UTF16 bool
+	// 非空时直接返回该错误,模拟进程无法启动
+	Err error
+	// 响应前等待,可被ctx取消
+	Delay time.Duration
+	// 输出后保持运行直到ctx取消,模拟常驻进程
+	Hold bool
+}
+
+// 一次被记录的调用
+type FakeCall struct {
+	Args  []string
+	Stdin string
+}
+
+// 参数匹配规则
+type FakeRule struct {
+	runner    *FakeRunner
+	pattern   []string
+	responses []FakeResponse
+	handler   func(FakeCall) FakeResponse
+	hits      int
+}
+
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// 登记规则,"*"匹配任意单个参数,末尾的"..."匹配其余全部参数,后登记的规则优先
+func (f *FakeRunner) On(args ...string) *FakeRule {
+	rule := &FakeRule{runner: f, pattern: args}
+	f.mu.Lock()
+	f.rules = append(f.rules, rule)
+	f.mu.Unlock()
+	return rule
+}
+
+// 依次返回的响应,用完后重复最后一个
+func (r *FakeRule) Reply(res ...FakeResponse) *FakeRule {
+	r.runner.mu.Lock()
+	r.responses = append(r.responses, res...)
+	r.runner.mu.Unlock()
+	return r
+}
+
+// 根据调用动态生成响应,优先于Reply
+func (r *FakeRule) Handle(fn func(FakeCall) FakeResponse) *FakeRule {
+	r.runner.mu.Lock()
+	r.handler = fn
+	r.runner.mu.Unlock()
+	return r
+}
+
+// 规则被匹配的次数
+func (r *FakeRule) Hits() int {
+	r.runner.mu.Lock()
+	defer r.runner.mu.Unlock()
+	return r.hits
+}
+
+// 按顺序返回所有调用
+func (f *FakeRunner) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// 与参数模式匹配的调用次数
+func (f *FakeRunner) Called(args ...string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if matchArgs(args, c.Args) {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *FakeRunner) Run(ctx context.Context, cmd Command) (Result, error) {
+	call := FakeCall{Args: append([]string(nil), cmd.Args...)}
+	if cmd.Stdin != nil {
+		in, _ := io.ReadAll(cmd.Stdin)
+		call.Stdin = string(in)
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	res, handler := f.respondLocked(call)
+	f.mu.Unlock()
+	if handler != nil {
+		res = handler(call)
+	}
+
+	if res.Delay > 0 {
+		t := time.NewTimer(res.Delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return Result{ExitCode: -1}, ctx.Err()
+		case <-t.C:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{ExitCode: -1}, err
+	}
+	if res.Err != nil {
+		return Result{ExitCode: -1}, res.Err
+	}
+
+	stdout, stderr := []byte(res.Stdout), []byte(res.Stderr)
+	if res.UTF16 {
+		stdout, stderr = EncodeUTF16LE(res.Stdout), EncodeUTF16LE(res.Stderr)
+	}
+	if cmd.Stdout != nil {
+		cmd.Stdout.Write(stdout)
+		stdout = nil
+	}
+	if res.Hold {
+		<-ctx.Done()
+		return Result{Stderr: stderr, Output: stderr, ExitCode: -1}, ctx.Err()
+	}
+	out := Result{
+		Output:   append(append([]byte{}, stdout...), stderr...),
+		Stdout:   stdout,
+		Stderr:   stderr,
+		ExitCode: res.ExitCode,
+	}
+	if res.ExitCode != 0 {
+		return out, &ExitError{Code: res.ExitCode}
+	}
+	return out, nil
+}
+
+// 查找匹配的规则并取出本次响应
+func (f *FakeRunner) respondLocked(call FakeCall) (FakeResponse, func(FakeCall) FakeResponse) {
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		rule := f.rules[i]
+		if !matchArgs(rule.pattern, call.Args) {
+			continue
+		}
+		n := rule.hits
+		rule.hits++
+		if rule.handler != nil {
+			return FakeResponse{}, rule.handler
+		}
+		if len(rule.responses) == 0 {
+			return FakeResponse{}, nil
+		}
+		return rule.responses[min(n, len(rule.responses)-1)], nil
+	}
+	if f.Fallback != nil {
+		return *f.Fallback, nil
+	}
+	return FakeResponse{
+		Stderr:   fmt.Sprintf("fake wsl.exe: 未登记的命令 %s\n", strings.Join(call.Args, " ")),
+		ExitCode: 1,
+	}, nil
+}
+
+func matchArgs(pattern, args []string) bool {
+	for i, p := range pattern {
+		if p == "..." && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(args) || (p != "*" && p != args[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(args)
+}
+
+// 按 wsl.exe 的方式编码为不带BOM的UTF-16LE
+func EncodeUTF16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	b := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		b = append(b, byte(u), byte(u>>8))
+	}
+	return b
+}
+
+// 模拟 wsl -l -v 的输出
+func FakeList(distros ...Distro) FakeResponse {
+	var sb strings.Builder
+	sb.WriteString("  NAME            STATE           VERSION\r\n")
+	for _, d := range distros {
+		mark := " "
+		if d.Default {
+			mark = "*"
+		}
+		fmt.Fprintf(&sb, "%s %-15s %-15s %d\r\n", mark, d.Name, d.State, d.Version)
+	}
+	return FakeResponse{Stdout: sb.String(), UTF16: true}
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package wslcli
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// 放入独立进程组,ctx结束时结束整个进程组
+func setupProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	// 子进程继承了输出管道时不无限等待
+	cmd.WaitDelay = 3 * time.Second
+}
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package wslcli
+
+import (
+	"os/exec"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// 隐藏控制台窗口,ctx结束时结束整个进程树
+func setupProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &windows.SysProcAttr{HideWindow: true}
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		kill.SysProcAttr = &windows.SysProcAttr{HideWindow: true}
+		if kill.Run() != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	// 子进程继承了输出管道时不无限等待
+	cmd.WaitDelay = 3 * time.Second
+}
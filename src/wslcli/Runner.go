@@ -0,0 +1,117 @@
+package wslcli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	audit "Golang-WSL-GUI/src/Audit"
+)
+
+// 一次 wsl.exe 调用
+type Command struct {
+	// wsl.exe 的参数,不含程序名
+	Args []string
+	// 标准输入,为空时不提供输入
+	Stdin io.Reader
+	// 非空时标准输出实时写入,不再保留在 Result 中,用于常驻进程
+	Stdout io.Writer
+	// 只读查询,如 wsl -l -v 轮询与指标采集,审计日志中做标记,查询时可隐藏
+	ReadOnly bool
+}
+
+// 执行 wsl.exe 命令,测试时可替换为 FakeRunner
+type Runner interface {
+	// 执行命令,进程以非0退出码结束时返回错误,Result 仍然有效
+	Run(ctx context.Context, cmd Command) (Result, error)
+}
+
+// 启动真实进程的执行器
+type ExecRunner struct {
+	// 可执行文件路径
+	Path string
+}
+
+func (r ExecRunner) Run(ctx context.Context, c Command) (Result, error) {
+	cmd := exec.CommandContext(ctx, r.Path, c.Args...)
+	var stdout, stderr bytes.Buffer
+	combined := &lockedBuffer{}
+	cmd.Stdout = io.MultiWriter(&stdout, combined)
+	if c.Stdout != nil {
+		cmd.Stdout = c.Stdout
+	}
+	cmd.Stderr = io.MultiWriter(&stderr, combined)
+	cmd.Stdin = c.Stdin
+	setupProcess(cmd)
+
+	err := cmd.Run()
+	res := Result{
+		Output: combined.Bytes(),
+		Stdout: stdout.Bytes(),
+		Stderr: stderr.Bytes(),
+	}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	} else {
+		res.ExitCode = -1
+	}
+	return res, err
+}
+
+// 标准输出与错误输出由不同goroutine写入,合并缓冲需要加锁
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+// 进程以非0退出码结束
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return "exit status " + strconv.Itoa(e.Code)
+}
+
+// 将每次调用写入审计日志的执行器
+type AuditRunner struct {
+	Next Runner
+	// 记录中的程序名
+	Program string
+}
+
+func (r AuditRunner) Run(ctx context.Context, cmd Command) (Result, error) {
+	start := time.Now()
+	res, err := r.Next.Run(ctx, cmd)
+	e := audit.Entry{
+		Time:       start,
+		Program:    r.Program,
+		Args:       cmd.Args,
+		ExitCode:   res.ExitCode,
+		DurationMs: time.Since(start).Milliseconds(),
+		Output:     res.Text(),
+		Initiator:  audit.InitiatorFrom(ctx),
+		ReadOnly:   cmd.ReadOnly,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	audit.Default.Record(e)
+	return res, err
+}
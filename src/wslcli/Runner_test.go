@@ -0,0 +1,57 @@
+package wslcli
+
+import (
+	"context"
+	"testing"
+
+	audit "Golang-WSL-GUI/src/Audit"
+)
+
+// 只读查询同样写入审计日志,查询时可隐藏成功的只读查询
+func TestAuditRunnerRecordsReadOnly(t *testing.T) {
+	log := audit.NewLog(t.TempDir())
+	prev := audit.Default
+	audit.Default = log
+	t.Cleanup(func() { audit.Default = prev })
+
+	f := NewFakeRunner()
+	f.On("--list", "--verbose").Reply(FakeList(Distro{Name: "U", State: StateRunning, Version: 2}))
+	f.On("-d", "U", "...")
+	f.On("--terminate", "U")
+	c := New(AuditRunner{Next: f, Program: "wsl.exe"})
+	ctx := context.Background()
+
+	c.List(ctx)
+	c.Exec(ctx, "U", ExecOptions{ReadOnly: true}, "cat", "/proc/meminfo")
+	c.Terminate(ctx, "U")
+	f.On("-d", "U", "...").Reply(FakeResponse{ExitCode: 1})
+	c.Exec(ctx, "U", ExecOptions{ReadOnly: true}, "cat", "/proc/meminfo")
+
+	entries, err := log.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("记录 %d 条, 期望全部4条命令: %+v", len(entries), entries)
+	}
+	readOnly := 0
+	for _, e := range entries {
+		if e.ReadOnly {
+			readOnly++
+		}
+		if e.Args[0] == "--terminate" && e.ReadOnly {
+			t.Fatalf("terminate 被标记为只读: %+v", e)
+		}
+	}
+	if readOnly != 3 {
+		t.Fatalf("只读记录 %d 条, 期望 3", readOnly)
+	}
+
+	visible, err := log.Query(audit.Filter{HideReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visible) != 2 {
+		t.Fatalf("隐藏只读后 %d 条, 期望 terminate 与失败的只读查询: %+v", len(visible), visible)
+	}
+}
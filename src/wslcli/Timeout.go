@@ -0,0 +1,107 @@
+package wslcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 超时错误的固定前缀,前端据此识别超时
+const TimeoutCode = "WSL_TIMEOUT"
+
+// 各操作的默认超时,为0时不限时,ctx截止时间更早时以ctx为准
+type Timeouts struct {
+	List       time.Duration
+	Terminate  time.Duration
+	Shutdown   time.Duration
+	Export     time.Duration
+	Import     time.Duration
+	Unregister time.Duration
+	Exec       time.Duration
+	Launch     time.Duration
+	Version    time.Duration
+}
+
+var DefaultTimeouts = Timeouts{
+	List:       15 * time.Second,
+	Terminate:  30 * time.Second,
+	Shutdown:   60 * time.Second,
+	Export:     2 * time.Hour,
+	Import:     2 * time.Hour,
+	Unregister: 5 * time.Minute,
+	Exec:       30 * time.Second,
+	Launch:     30 * time.Second,
+	Version:    15 * time.Second,
+}
+
+// 操作在限定时间内未完成,进程已被结束
+type TimeoutError struct {
+	// 超时的操作,如 wsl.exe --list --verbose
+	Op      string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s 在 %s 内未完成,WSL可能无响应", TimeoutCode, e.Op, e.Timeout.Round(time.Millisecond))
+}
+
+// 使 errors.Is(err, context.DeadlineExceeded) 成立
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// 是否为超时错误
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// 在ctx上附加默认超时,返回实际生效的时限
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc, time.Duration) {
+	limit := d
+	if deadline, ok := ctx.Deadline(); ok && (d <= 0 || time.Until(deadline) < d) {
+		limit = time.Until(deadline)
+	}
+	if d <= 0 {
+		return ctx, func() {}, limit
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, cancel, limit
+}
+
+// 轮询等待发行版停止运行
+func WaitStopped(ctx context.Context, c Client, name string, timeout time.Duration) error {
+	ctx, cancel, limit := withTimeout(ctx, timeout)
+	defer cancel()
+
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		distros, err := c.List(ctx)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		if err == nil && !isRunning(distros, name) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return &TimeoutError{Op: fmt.Sprintf("等待发行版 %s 停止", name), Timeout: limit}
+			}
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func isRunning(distros []Distro, name string) bool {
+	for _, d := range distros {
+		if strings.EqualFold(d.Name, name) {
+			return d.State == StateRunning
+		}
+	}
+	return false
+}
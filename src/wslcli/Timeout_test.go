@@ -0,0 +1,70 @@
+package wslcli
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestListTimeout(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("--list", "--verbose").Reply(FakeResponse{Delay: time.Second})
+	c := New(f)
+	c.Timeouts.List = 50 * time.Millisecond
+
+	_, err := c.List(context.Background())
+	if !IsTimeout(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, 期望超时错误", err)
+	}
+
+	// 调用方取消不算超时
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = c.List(ctx)
+	if !errors.Is(err, context.Canceled) || IsTimeout(err) {
+		t.Fatalf("err = %v, 期望 context.Canceled", err)
+	}
+}
+
+func TestWaitStopped(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("--list", "--verbose").Reply(FakeList(Distro{Name: "U", State: StateRunning, Version: 2}))
+	if err := WaitStopped(context.Background(), New(f), "U", 1500*time.Millisecond); !IsTimeout(err) {
+		t.Fatalf("err = %v, 一直运行时应超时", err)
+	}
+
+	f.On("--list", "--verbose").Reply(
+		FakeList(Distro{Name: "U", State: StateRunning, Version: 2}),
+		FakeList(Distro{Name: "U", State: StateStopped, Version: 2}),
+	)
+	if err := WaitStopped(context.Background(), New(f), "U", 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 超时时结束整个进程树,继承了输出管道的子进程不会拖住调用方
+func TestTimeoutKillsProcessTree(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("没有 sh")
+	}
+	c := New(ExecRunner{Path: sh})
+	start := time.Now()
+	_, err = c.run(context.Background(), 300*time.Millisecond, nil, "-c", "sleep 30 & sleep 30")
+	if !IsTimeout(err) {
+		t.Fatalf("err = %v, 期望超时错误", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("超时后等待了 %s", elapsed)
+	}
+
+	res, err := c.run(context.Background(), time.Second, nil, "-c", "echo out; echo err >&2; exit 3")
+	if err == nil || res.ExitCode != 3 {
+		t.Fatalf("ExitCode = %d, err = %v", res.ExitCode, err)
+	}
+	if string(res.Stdout) != "out\n" || string(res.Stderr) != "err\n" {
+		t.Fatalf("Stdout = %q, Stderr = %q", res.Stdout, res.Stderr)
+	}
+}
@@ -0,0 +1,219 @@
+package wslcli
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// 发行版内的配置文件
+	WslConfPath = "/etc/wsl.conf"
+	// 写入时使用的临时文件,与目标在同一目录,mv 时不会跨文件系统
+	wslConfTemp = "/etc/.wsl.conf.easy-wsl-gui.tmp"
+)
+
+// 文件不存在时输出为空并正常退出,只有读取失败才返回非零状态
+const readWslConfScript = `if [ -e ` + WslConfPath + ` ]; then cat ` + WslConfPath + `; fi`
+
+// 写入临时文件后替换,失败时删除临时文件
+const writeWslConfScript = `umask 022 && cat > ` + wslConfTemp + ` && mv -f ` + wslConfTemp + ` ` + WslConfPath + ` || { rm -f ` + wslConfTemp + `; exit 1; }`
+
+// /etc/wsl.conf 的内容,按行保存,修改时保留注释、空行与未知的段和键
+type WslConf struct {
+	lines []confLine
+}
+
+type confLine struct {
+	text string
+	// 所在段,小写
+	section string
+	// 键名,小写,非键值行为空
+	key   string
+	value string
+}
+
+// 解析 wsl.conf,段名与键名不区分大小写
+func ParseWslConf(text string) *WslConf {
+	c := &WslConf{}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return c
+	}
+	section := ""
+	for _, raw := range strings.Split(text, "\n") {
+		line := confLine{text: raw}
+		trimmed := strings.TrimSpace(raw)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			section = strings.ToLower(strings.TrimSpace(trimmed[1 : len(trimmed)-1]))
+		default:
+			if k, v, ok := strings.Cut(trimmed, "="); ok {
+				line.key = strings.ToLower(strings.TrimSpace(k))
+				line.value = unquote(strings.TrimSpace(v))
+			}
+		}
+		line.section = section
+		c.lines = append(c.lines, line)
+	}
+	return c
+}
+
+func unquote(v string) string {
+	if len(v) >= 2 && (v[0] == '"' && v[len(v)-1] == '"' || v[0] == '\'' && v[len(v)-1] == '\'') {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// 读取键值,同一键出现多次时以最后一次为准
+func (c *WslConf) Get(section, key string) (string, bool) {
+	section, key = strings.ToLower(section), strings.ToLower(key)
+	value, found := "", false
+	for _, l := range c.lines {
+		if l.section == section && l.key == key {
+			value, found = l.value, true
+		}
+	}
+	return value, found
+}
+
+// 设置键值: 修改最后一次出现的位置并删除重复项,
+// 没有该键时追加到段末尾,没有该段时在文件末尾新建
+func (c *WslConf) Set(section, key, value string) {
+	ls, lk := strings.ToLower(section), strings.ToLower(key)
+	last, sectionEnd := -1, -1
+	for i, l := range c.lines {
+		if l.section != ls {
+			continue
+		}
+		if l.key == lk {
+			last = i
+		}
+		if l.key != "" || isHeader(l.text) {
+			sectionEnd = i
+		}
+	}
+
+	switch {
+	case last >= 0:
+		l := &c.lines[last]
+		eq := strings.Index(l.text, "=")
+		prefix := l.text[:eq+1]
+		if rest := l.text[eq+1:]; strings.HasPrefix(rest, " ") {
+			prefix += " "
+		}
+		l.text, l.value = prefix+value, value
+	case sectionEnd >= 0:
+		line := confLine{text: key + "=" + value, section: ls, key: lk, value: value}
+		c.lines = append(c.lines[:sectionEnd+1], append([]confLine{line}, c.lines[sectionEnd+1:]...)...)
+		last = sectionEnd + 1
+	default:
+		if n := len(c.lines); n > 0 && strings.TrimSpace(c.lines[n-1].text) != "" {
+			c.lines = append(c.lines, confLine{section: c.lines[n-1].section})
+		}
+		c.lines = append(c.lines,
+			confLine{text: "[" + section + "]", section: ls},
+			confLine{text: key + "=" + value, section: ls, key: lk, value: value},
+		)
+		return
+	}
+	c.remove(ls, lk, last)
+	c.dropEmptyDuplicates(ls)
+}
+
+// 删除键,返回是否存在
+func (c *WslConf) Delete(section, key string) bool {
+	n := len(c.lines)
+	c.remove(strings.ToLower(section), strings.ToLower(key), -1)
+	return len(c.lines) != n
+}
+
+// 删除段中除keep位置外的该键
+func (c *WslConf) remove(section, key string, keep int) {
+	kept := c.lines[:0]
+	for i, l := range c.lines {
+		if i != keep && l.section == section && l.key == key {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	c.lines = kept
+}
+
+// 同名段出现多次时,删除其中只剩空行的段
+func (c *WslConf) dropEmptyDuplicates(section string) {
+	var headers []int
+	for i, l := range c.lines {
+		if l.section == section && isHeader(l.text) {
+			headers = append(headers, i)
+		}
+	}
+	if len(headers) < 2 {
+		return
+	}
+	drop := map[int]bool{}
+	kept := len(headers)
+	for _, h := range headers {
+		end := h + 1
+		empty := true
+		for ; end < len(c.lines) && !isHeader(c.lines[end].text); end++ {
+			if strings.TrimSpace(c.lines[end].text) != "" {
+				empty = false
+			}
+		}
+		if !empty || kept == 1 {
+			continue
+		}
+		kept--
+		for i := h; i < end; i++ {
+			drop[i] = true
+		}
+	}
+	lines := c.lines[:0]
+	for i, l := range c.lines {
+		if !drop[i] {
+			lines = append(lines, l)
+		}
+	}
+	c.lines = lines
+}
+
+func isHeader(text string) bool {
+	t := strings.TrimSpace(text)
+	return strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]")
+}
+
+// 序列化,以换行结尾
+func (c *WslConf) String() string {
+	if len(c.lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, l := range c.lines {
+		b.WriteString(l.text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// 读取发行版的 wsl.conf,文件不存在时返回空配置;按退出状态判断,不依赖本地化的错误文本
+func ReadWslConf(ctx context.Context, c Client, name string) (*WslConf, error) {
+	res, err := c.Exec(ctx, name, ExecOptions{}, "sh", "-c", readWslConfScript)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWslConf(res.Text()), nil
+}
+
+// 读取wsl.conf,由fn修改后以root身份经标准输入写入临时文件,再用mv替换原文件
+func UpdateWslConf(ctx context.Context, c Client, name string, fn func(conf *WslConf)) error {
+	conf, err := ReadWslConf(ctx, c, name)
+	if err != nil {
+		return err
+	}
+	fn(conf)
+	_, err = c.Exec(ctx, name, ExecOptions{User: "root", Stdin: strings.NewReader(conf.String())}, "sh", "-c", writeWslConfScript)
+	return err
+}
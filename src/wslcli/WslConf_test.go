@@ -0,0 +1,77 @@
+package wslcli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// 修改时保留注释与其他段,重复的 [user] 段合并为一个
+func TestWslConfEdit(t *testing.T) {
+	c := ParseWslConf("# comment\n[boot]\nsystemd=true\n\n[user]\ndefault=alice\n\n[user]\ndefault=bob\n\n[network]\nhostname = x\n")
+	if v, _ := c.Get("user", "default"); v != "bob" {
+		t.Fatalf("default = %q, 期望最后一个段的值", v)
+	}
+	c.Set("user", "default", "carol")
+	c.Set("network", "hostname", "y")
+	c.Set("interop", "enabled", "false")
+	c.Set("boot", "command", "echo hi")
+
+	want := "# comment\n[boot]\nsystemd=true\ncommand=echo hi\n\n[user]\ndefault=carol\n\n[network]\nhostname = y\n\n[interop]\nenabled=false\n"
+	if got := c.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if !c.Delete("boot", "command") || c.Delete("boot", "command") {
+		t.Fatal("Delete 应只删除一次")
+	}
+
+	e := ParseWslConf("")
+	e.Set("user", "default", "bob")
+	if got := e.String(); got != "[user]\ndefault=bob\n" {
+		t.Fatalf("空配置 = %q", got)
+	}
+}
+
+func TestReadWslConf(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("-d", "Ubuntu", "--exec", "sh", "-c", readWslConfScript).Reply(FakeResponse{Stdout: "[user]\ndefault=bob\n"})
+	// 文件不存在时脚本无输出并正常退出
+	f.On("-d", "Empty", "--exec", "sh", "-c", readWslConfScript)
+	// 读取失败时按退出状态报错,即使输出为本地化文本
+	f.On("-d", "Locked", "--exec", "sh", "-c", readWslConfScript).Reply(FakeResponse{ExitCode: 1, Stdout: "cat: /etc/wsl.conf: Keine Berechtigung\n"})
+	c := New(f)
+	ctx := context.Background()
+
+	conf, err := ReadWslConf(ctx, c, "Ubuntu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := conf.Get("user", "default"); v != "bob" {
+		t.Fatalf("default = %q", v)
+	}
+	if conf, err := ReadWslConf(ctx, c, "Empty"); err != nil || conf.String() != "" {
+		t.Fatalf("文件不存在时 = %q, %v", conf, err)
+	}
+	if _, err := ReadWslConf(ctx, c, "Locked"); err == nil {
+		t.Fatal("读取失败时没有返回错误")
+	}
+}
+
+// 写入经标准输入传递,不出现在命令行中
+func TestUpdateWslConf(t *testing.T) {
+	f := NewFakeRunner()
+	f.On("-d", "Ubuntu", "--exec", "sh", "-c", readWslConfScript).Reply(FakeResponse{Stdout: "[boot]\nsystemd=true\n"})
+	f.On("-d", "Ubuntu", "-u", "root", "--exec", "sh", "-c", writeWslConfScript)
+
+	err := UpdateWslConf(context.Background(), New(f), "Ubuntu", func(conf *WslConf) {
+		conf.Set("user", "default", "bob")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := f.Calls()
+	last := calls[len(calls)-1]
+	if last.Stdin != "[boot]\nsystemd=true\n\n[user]\ndefault=bob\n" || strings.Contains(strings.Join(last.Args, " "), "bob") {
+		t.Fatalf("写入 = %v %q", last.Args, last.Stdin)
+	}
+}